@@ -37,6 +37,7 @@ import (
 	"github.com/gogo/protobuf/types"
 	"google.golang.org/grpc"
 	"sort"
+	"sync"
 	"time"
 )
 
@@ -50,11 +51,18 @@ func NewClient(address string, opts ...Option) (*Client, error) {
 		return nil, err
 	}
 
+	var watches *session.WatchLimiter
+	if options.maxWatchStreams > 0 {
+		watches = session.NewWatchLimiter(options.maxWatchStreams, options.watchPolicy)
+	}
+
 	return &Client{
 		conn:        conn,
 		application: options.application,
 		namespace:   options.namespace,
 		conns:       []*grpc.ClientConn{},
+		watches:     watches,
+		sessions:    session.NewRegistry(),
 	}, nil
 }
 
@@ -64,6 +72,70 @@ type Client struct {
 	namespace   string
 	conn        *grpc.ClientConn
 	conns       []*grpc.ClientConn
+	watches     *session.WatchLimiter
+	sessions    *session.Registry
+	parent      *Client
+	namespaces  map[string]*Client
+	closed      bool
+	mu          sync.Mutex
+}
+
+// Namespace returns a client bound to the given namespace
+// Partition groups are scoped to a namespace, so primitives created by the returned client are independent of
+// primitives with the same names in other namespaces. The namespaced client shares the connection and limits of the
+// client from which it was created. Calls for the same namespace return the same client until it's closed. Closing
+// it closes the sessions of its own primitives, while closing the parent client closes the primitives of all its
+// namespaced clients.
+func (c *Client) Namespace(namespace string) *Client {
+	if c.parent != nil {
+		return c.parent.Namespace(namespace)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if client, ok := c.namespaces[namespace]; ok {
+		return client
+	}
+	client := &Client{
+		application: c.application,
+		namespace:   namespace,
+		conn:        c.conn,
+		watches:     c.watches,
+		sessions:    session.NewRegistry(),
+		parent:      c,
+	}
+	if c.closed {
+		client.Close()
+	} else {
+		if c.namespaces == nil {
+			c.namespaces = make(map[string]*Client)
+		}
+		c.namespaces[namespace] = client
+	}
+	return client
+}
+
+// removeNamespace removes a closed namespaced client
+func (c *Client) removeNamespace(client *Client) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.namespaces[client.namespace] == client {
+		delete(c.namespaces, client.namespace)
+	}
+}
+
+// Sessions returns the number of open sessions held by the client's primitives
+func (c *Client) Sessions() int {
+	return c.sessions.Sessions()
+}
+
+// WatchStreams returns the number of watch streams currently open by the client's primitives
+// Watch streams are only counted if the number of streams is limited with WithMaxWatchStreams.
+func (c *Client) WatchStreams() int {
+	if c.watches == nil {
+		return 0
+	}
+	return c.watches.Streams()
 }
 
 // CreateGroup creates a new partition group
@@ -125,6 +197,15 @@ func (c *Client) GetGroups(ctx context.Context) ([]*PartitionGroup, error) {
 
 // GetGroup returns a partition group primitive client
 func (c *Client) GetGroup(ctx context.Context, name string) (*PartitionGroup, error) {
+	groupProto, err := c.getGroup(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return c.newGroup(groupProto)
+}
+
+// getGroup gets the partition group with the given name from the controller
+func (c *Client) getGroup(ctx context.Context, name string) (*controllerapi.PartitionGroup, error) {
 	client := controllerapi.NewControllerServiceClient(c.conn)
 	request := &controllerapi.GetPartitionGroupsRequest{
 		ID: &controllerapi.PartitionGroupId{
@@ -143,7 +224,7 @@ func (c *Client) GetGroup(ctx context.Context, name string) (*PartitionGroup, er
 	} else if len(response.Groups) > 1 {
 		return nil, errors.New("partition group " + name + " is ambiguous")
 	}
-	return c.newGroup(response.Groups[0])
+	return response.Groups[0], nil
 }
 
 func (c *Client) newGroup(groupProto *controllerapi.PartitionGroup) (*PartitionGroup, error) {
@@ -167,9 +248,21 @@ func (c *Client) newGroup(groupProto *controllerapi.PartitionGroup) (*PartitionG
 		PartitionSize: int(groupProto.Spec.PartitionSize),
 		application:   c.application,
 		partitions:    partitions,
+		watches:       c.watches,
+		sessions:      c.sessions,
 	}, nil
 }
 
+// GetPrimitives gets a list of primitives of the given types in the named partition group
+// If no types are specified, primitives of all types are returned.
+func (c *Client) GetPrimitives(ctx context.Context, group string, types ...primitive.Type) ([]*primitiveapi.PrimitiveInfo, error) {
+	partitionGroup, err := c.GetGroup(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+	return partitionGroup.GetPrimitives(ctx, types...)
+}
+
 // DeleteGroup deletes a partition group via the controller
 func (c *Client) DeleteGroup(ctx context.Context, name string) error {
 	client := controllerapi.NewControllerServiceClient(c.conn)
@@ -184,17 +277,40 @@ func (c *Client) DeleteGroup(ctx context.Context, name string) error {
 }
 
 // Close closes the client
+// The sessions of all primitives created by the client that are still open are closed along with the client's
+// connections. All resources are released even if some fail to close, and the first error is returned. Once the
+// client is closed, primitives can no longer be created from its partition groups. Closing a closed client is a no-op.
 func (c *Client) Close() error {
-	var result error
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	namespaces := c.namespaces
+	c.namespaces = nil
+	c.mu.Unlock()
+
+	result := c.sessions.Close()
+	if c.parent != nil {
+		// The parent client owns the connection
+		c.parent.removeNamespace(c)
+		return result
+	}
+	for _, client := range namespaces {
+		if err := client.Close(); err != nil && result == nil {
+			result = err
+		}
+	}
 	for _, conn := range c.conns {
 		err := conn.Close()
-		if err != nil {
+		if err != nil && result == nil {
 			result = err
 		}
 	}
 
-	if err := c.conn.Close(); err != nil {
-		return err
+	if err := c.conn.Close(); err != nil && result == nil {
+		result = err
 	}
 	return result
 }
@@ -208,6 +324,20 @@ type PartitionGroup struct {
 
 	application string
 	partitions  []net.Address
+	watches     *session.WatchLimiter
+	sessions    *session.Registry
+}
+
+// sessionOptions returns the session options for a primitive in the group, including options inherited from the client
+func (g *PartitionGroup) sessionOptions(opts []session.Option) []session.Option {
+	inherited := []session.Option{}
+	if g.watches != nil {
+		inherited = append(inherited, session.WithWatchLimiter(g.watches))
+	}
+	if g.sessions != nil {
+		inherited = append(inherited, session.WithRegistry(g.sessions))
+	}
+	return append(inherited, opts...)
 }
 
 // GetPrimitives gets a list of primitives of the given types
@@ -270,45 +400,45 @@ func (g *PartitionGroup) getPrimitives(ctx context.Context, t primitive.Type) ([
 
 // GetCounter gets or creates a Counter with the given name
 func (g *PartitionGroup) GetCounter(ctx context.Context, name string, opts ...session.Option) (counter.Counter, error) {
-	return counter.New(ctx, primitive.NewName(g.Namespace, g.Name, g.application, name), g.partitions, opts...)
+	return counter.New(ctx, primitive.NewName(g.Namespace, g.Name, g.application, name), g.partitions, g.sessionOptions(opts)...)
 }
 
 // GetElection gets or creates an Election with the given name
 func (g *PartitionGroup) GetElection(ctx context.Context, name string, opts ...session.Option) (election.Election, error) {
-	return election.New(ctx, primitive.NewName(g.Namespace, g.Name, g.application, name), g.partitions, opts...)
+	return election.New(ctx, primitive.NewName(g.Namespace, g.Name, g.application, name), g.partitions, g.sessionOptions(opts)...)
 }
 
 // GetIndexedMap gets or creates a Map with the given name
 func (g *PartitionGroup) GetIndexedMap(ctx context.Context, name string, opts ...session.Option) (indexedmap.IndexedMap, error) {
-	return indexedmap.New(ctx, primitive.NewName(g.Namespace, g.Name, g.application, name), g.partitions, opts...)
+	return indexedmap.New(ctx, primitive.NewName(g.Namespace, g.Name, g.application, name), g.partitions, g.sessionOptions(opts)...)
 }
 
 // GetLeaderLatch gets or creates a LeaderLatch with the given name
 func (g *PartitionGroup) GetLeaderLatch(ctx context.Context, name string, opts ...session.Option) (leader.Latch, error) {
-	return leader.New(ctx, primitive.NewName(g.Namespace, g.Name, g.application, name), g.partitions, opts...)
+	return leader.New(ctx, primitive.NewName(g.Namespace, g.Name, g.application, name), g.partitions, g.sessionOptions(opts)...)
 }
 
 // GetList gets or creates a List with the given name
 func (g *PartitionGroup) GetList(ctx context.Context, name string, opts ...session.Option) (list.List, error) {
-	return list.New(ctx, primitive.NewName(g.Namespace, g.Name, g.application, name), g.partitions, opts...)
+	return list.New(ctx, primitive.NewName(g.Namespace, g.Name, g.application, name), g.partitions, g.sessionOptions(opts)...)
 }
 
 // GetLock gets or creates a Lock with the given name
 func (g *PartitionGroup) GetLock(ctx context.Context, name string, opts ...session.Option) (lock.Lock, error) {
-	return lock.New(ctx, primitive.NewName(g.Namespace, g.Name, g.application, name), g.partitions, opts...)
+	return lock.New(ctx, primitive.NewName(g.Namespace, g.Name, g.application, name), g.partitions, g.sessionOptions(opts)...)
 }
 
 // GetMap gets or creates a Map with the given name
 func (g *PartitionGroup) GetMap(ctx context.Context, name string, opts ...session.Option) (_map.Map, error) {
-	return _map.New(ctx, primitive.NewName(g.Namespace, g.Name, g.application, name), g.partitions, opts...)
+	return _map.New(ctx, primitive.NewName(g.Namespace, g.Name, g.application, name), g.partitions, g.sessionOptions(opts)...)
 }
 
 // GetSet gets or creates a Set with the given name
 func (g *PartitionGroup) GetSet(ctx context.Context, name string, opts ...session.Option) (set.Set, error) {
-	return set.New(ctx, primitive.NewName(g.Namespace, g.Name, g.application, name), g.partitions, opts...)
+	return set.New(ctx, primitive.NewName(g.Namespace, g.Name, g.application, name), g.partitions, g.sessionOptions(opts)...)
 }
 
 // GetValue gets or creates a Value with the given name
 func (g *PartitionGroup) GetValue(ctx context.Context, name string, opts ...session.Option) (value.Value, error) {
-	return value.New(ctx, primitive.NewName(g.Namespace, g.Name, g.application, name), g.partitions, opts...)
+	return value.New(ctx, primitive.NewName(g.Namespace, g.Name, g.application, name), g.partitions, g.sessionOptions(opts)...)
 }
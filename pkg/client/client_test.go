@@ -16,20 +16,26 @@ package client
 
 import (
 	"context"
+	controllerapi "github.com/atomix/api/proto/atomix/controller"
 	"github.com/atomix/go-client/pkg/client/counter"
 	"github.com/atomix/go-client/pkg/client/election"
 	"github.com/atomix/go-client/pkg/client/indexedmap"
 	"github.com/atomix/go-client/pkg/client/list"
 	"github.com/atomix/go-client/pkg/client/lock"
 	"github.com/atomix/go-client/pkg/client/map"
+	"github.com/atomix/go-client/pkg/client/session"
 	"github.com/atomix/go-client/pkg/client/set"
 	"github.com/atomix/go-client/pkg/client/test"
+	"github.com/atomix/go-client/pkg/client/util/net"
 	"github.com/atomix/go-client/pkg/client/value"
 	"github.com/atomix/go-framework/pkg/atomix/registry"
 	"github.com/atomix/go-local/pkg/atomix/local"
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"testing"
+	"time"
 )
 
 func TestClient(t *testing.T) {
@@ -77,11 +83,27 @@ func TestClient(t *testing.T) {
 	val, err := group.GetValue(context.TODO(), "foo")
 	assert.NoError(t, err)
 	assert.NotNil(t, val)
+	assert.Equal(t, 1, client.Sessions())
 	_, err = val.Set(context.TODO(), []byte("bar"))
 	assert.NoError(t, err)
 	_, _, err = val.Get(context.TODO())
 	assert.NoError(t, err)
 
+	primitives, err := client.GetPrimitives(context.TODO(), "test")
+	assert.NoError(t, err)
+	assert.Len(t, primitives, 1)
+
+	primitives, err = client.GetPrimitives(context.TODO(), "test", value.Type)
+	assert.NoError(t, err)
+	assert.Len(t, primitives, 1)
+
+	primitives, err = client.GetPrimitives(context.TODO(), "test", counter.Type)
+	assert.NoError(t, err)
+	assert.Len(t, primitives, 0)
+
+	_, err = client.GetPrimitives(context.TODO(), "none")
+	assert.EqualError(t, err, "unknown partition group none")
+
 	err = client.DeleteGroup(context.TODO(), "test")
 	assert.NoError(t, err)
 
@@ -92,7 +114,13 @@ func TestClient(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, groups, 0)
 
-	client.Close()
+	err = client.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, client.Sessions())
+	assert.False(t, val.Sessions()[0].Healthy())
+
+	err = client.Close()
+	assert.NoError(t, err)
 }
 
 func TestPartitionGroup(t *testing.T) {
@@ -201,3 +229,167 @@ func TestPartitionGroup(t *testing.T) {
 
 	test.StopTestPartitions(partitions)
 }
+
+func TestGroupStatus(t *testing.T) {
+	groupProto := &controllerapi.PartitionGroup{
+		ID: &controllerapi.PartitionGroupId{
+			Namespace: "default",
+			Name:      "test",
+		},
+		Spec: &controllerapi.PartitionGroupSpec{
+			Partitions:    3,
+			PartitionSize: 1,
+		},
+		Partitions: []*controllerapi.Partition{
+			{
+				PartitionID: 1,
+				Endpoints:   []*controllerapi.PartitionEndpoint{{Host: "test-1", Port: 5678}},
+			},
+			{
+				PartitionID: 2,
+				Endpoints:   []*controllerapi.PartitionEndpoint{{Host: "test-2", Port: 5678}},
+			},
+		},
+	}
+
+	// Partitions become ready one at a time
+	ready := map[net.Address]bool{}
+	probe := func(ctx context.Context, address net.Address) error {
+		if ready[address] {
+			return nil
+		}
+		return status.Error(codes.Unavailable, "unavailable")
+	}
+
+	groupStatus, err := newGroupStatus(context.TODO(), groupProto, probe)
+	assert.NoError(t, err)
+	assert.Equal(t, "default", groupStatus.Namespace)
+	assert.Equal(t, "test", groupStatus.Name)
+	assert.Len(t, groupStatus.Partitions, 3)
+	assert.Equal(t, PartitionPending, groupStatus.Partitions[0].State)
+	assert.Equal(t, PartitionPending, groupStatus.Partitions[1].State)
+	assert.Equal(t, PartitionPending, groupStatus.Partitions[2].State)
+	assert.Equal(t, net.Address(""), groupStatus.Partitions[2].Address)
+	assert.False(t, groupStatus.Ready())
+
+	ready["test-1:5678"] = true
+	groupStatus, err = newGroupStatus(context.TODO(), groupProto, probe)
+	assert.NoError(t, err)
+	assert.Equal(t, PartitionReady, groupStatus.Partitions[0].State)
+	assert.Equal(t, PartitionPending, groupStatus.Partitions[1].State)
+	assert.False(t, groupStatus.Ready())
+
+	ready["test-2:5678"] = true
+	ready["test-3:5678"] = true
+	groupProto.Partitions = append(groupProto.Partitions, &controllerapi.Partition{
+		PartitionID: 3,
+		Endpoints:   []*controllerapi.PartitionEndpoint{{Host: "test-3", Port: 5678}},
+	})
+	groupStatus, err = newGroupStatus(context.TODO(), groupProto, probe)
+	assert.NoError(t, err)
+	assert.Equal(t, net.Address("test-3:5678"), groupStatus.Partitions[2].Address)
+	assert.True(t, groupStatus.Ready())
+
+	groupStatus, err = newGroupStatus(context.TODO(), groupProto, func(ctx context.Context, address net.Address) error {
+		if address == "test-2:5678" {
+			return status.Error(codes.Internal, "internal error")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, PartitionFailed, groupStatus.Partitions[1].State)
+	assert.False(t, groupStatus.Ready())
+
+	// Probes are bounded by the caller's context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	_, err = newGroupStatus(ctx, groupProto, func(ctx context.Context, address net.Address) error {
+		select {
+		case <-ctx.Done():
+			return status.Error(codes.Canceled, ctx.Err().Error())
+		case <-time.After(ProbeTimeout):
+			return nil
+		}
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+func TestClientNamespaces(t *testing.T) {
+	controller := local.NewController(5680, registry.Registry)
+	err := controller.Start()
+	assert.NoError(t, err)
+	defer controller.Stop()
+
+	client, err := NewClient("localhost:5680", WithNamespace("default"), WithApplication("test"))
+	assert.NoError(t, err)
+
+	foo := client.Namespace("foo")
+	bar := client.Namespace("bar")
+	assert.True(t, foo == client.Namespace("foo"))
+	assert.True(t, foo == bar.Namespace("foo"))
+
+	fooGroup, err := foo.CreateGroup(context.TODO(), "test", 1, 1, &empty.Empty{})
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", fooGroup.Namespace)
+	barGroup, err := bar.CreateGroup(context.TODO(), "test", 1, 1, &empty.Empty{})
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", barGroup.Namespace)
+
+	_, err = client.GetGroup(context.TODO(), "test")
+	assert.EqualError(t, err, "unknown partition group test")
+
+	fooValue, err := fooGroup.GetValue(context.TODO(), "value")
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", fooValue.Name().Namespace)
+	barValue, err := barGroup.GetValue(context.TODO(), "value")
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", barValue.Name().Namespace)
+
+	_, err = fooValue.Set(context.TODO(), []byte("foo"))
+	assert.NoError(t, err)
+	_, err = barValue.Set(context.TODO(), []byte("bar"))
+	assert.NoError(t, err)
+
+	val, _, err := fooValue.Get(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", string(val))
+	val, _, err = barValue.Get(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", string(val))
+
+	// Closing a namespaced client only closes its own primitives
+	err = foo.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, foo.Sessions())
+	assert.Equal(t, 1, bar.Sessions())
+	assert.True(t, foo != client.Namespace("foo"))
+
+	err = client.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, bar.Sessions())
+}
+
+func TestClientNamespaceCache(t *testing.T) {
+	client := &Client{
+		namespace: "default",
+		sessions:  session.NewRegistry(),
+	}
+
+	foo := client.Namespace("foo")
+	assert.True(t, foo == client.Namespace("foo"))
+	assert.True(t, foo == foo.Namespace("foo"))
+	assert.True(t, foo != client.Namespace("bar"))
+	assert.Len(t, client.namespaces, 2)
+
+	// Closing a namespaced client removes it from its parent
+	assert.NoError(t, foo.Close())
+	assert.Len(t, client.namespaces, 1)
+	assert.NoError(t, foo.Close())
+	assert.Len(t, client.namespaces, 1)
+
+	bar := client.Namespace("foo")
+	assert.True(t, foo != bar)
+	assert.Len(t, client.namespaces, 2)
+}
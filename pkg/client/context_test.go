@@ -0,0 +1,39 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestWithDefaultTimeout(t *testing.T) {
+	ctx, cancel := WithDefaultTimeout(context.Background(), time.Second)
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.True(t, time.Until(deadline) <= time.Second)
+
+	parent, parentCancel := context.WithTimeout(context.Background(), time.Minute)
+	defer parentCancel()
+	expected, _ := parent.Deadline()
+	ctx, cancel = WithDefaultTimeout(parent, time.Second)
+	defer cancel()
+	deadline, ok = ctx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, expected, deadline)
+}
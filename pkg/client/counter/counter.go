@@ -20,7 +20,6 @@ import (
 	"github.com/atomix/api/proto/atomix/headers"
 	"github.com/atomix/go-client/pkg/client/primitive"
 	"github.com/atomix/go-client/pkg/client/session"
-	"github.com/atomix/go-client/pkg/client/util"
 	"github.com/atomix/go-client/pkg/client/util/net"
 	"google.golang.org/grpc"
 )
@@ -28,6 +27,20 @@ import (
 // Type is the counter type
 const Type primitive.Type = "Counter"
 
+// Operation names used to identify counter operations in traces and metrics
+const (
+	getOp       = "counter.Get"
+	setOp       = "counter.Set"
+	incrementOp = "counter.Increment"
+	decrementOp = "counter.Decrement"
+	resetOp     = "counter.Reset"
+	getAndAddOp = "counter.GetAndAdd"
+	addAndGetOp = "counter.AddAndGet"
+)
+
+// ErrNotFound is returned by Delete when the primitive does not exist
+var ErrNotFound = primitive.ErrNotFound
+
 // Client provides an API for creating Counters
 type Client interface {
 	// GetCounter gets the Counter instance of the given name
@@ -38,6 +51,9 @@ type Client interface {
 type Counter interface {
 	primitive.Primitive
 
+	// Sessions returns the sessions used by the primitive, one for each partition in which it's stored
+	Sessions() []*session.Session
+
 	// Get gets the current value of the counter
 	Get(ctx context.Context) (int64, error)
 
@@ -49,11 +65,26 @@ type Counter interface {
 
 	// Decrement decrements the counter by the given delta
 	Decrement(ctx context.Context, delta int64) (int64, error)
+
+	// Reset atomically sets the counter to zero and returns its previous value
+	Reset(ctx context.Context) (int64, error)
+
+	// GetAndIncrement atomically increments the counter by one and returns its previous value
+	GetAndIncrement(ctx context.Context) (int64, error)
+
+	// IncrementAndGet atomically increments the counter by one and returns its updated value
+	IncrementAndGet(ctx context.Context) (int64, error)
+
+	// GetAndAdd atomically adds the given delta to the counter and returns its previous value
+	GetAndAdd(ctx context.Context, delta int64) (int64, error)
+
+	// AddAndGet atomically adds the given delta to the counter and returns its updated value
+	AddAndGet(ctx context.Context, delta int64) (int64, error)
 }
 
 // New creates a new counter for the given partitions
 func New(ctx context.Context, name primitive.Name, partitions []net.Address, opts ...session.Option) (Counter, error) {
-	i, err := util.GetPartitionIndex(name.Name, len(partitions))
+	i, err := session.GetPartitioner(opts...)(name.Name, len(partitions))
 	if err != nil {
 		return nil, err
 	}
@@ -64,23 +95,23 @@ func New(ctx context.Context, name primitive.Name, partitions []net.Address, opt
 	}
 
 	return &counter{
-		name:    name,
+		Base:    primitive.NewBase(Type, name),
 		session: sess,
 	}, nil
 }
 
 // counter is the single partition implementation of Counter
 type counter struct {
-	name    primitive.Name
+	primitive.Base
 	session *session.Session
 }
 
-func (c *counter) Name() primitive.Name {
-	return c.name
+func (c *counter) Sessions() []*session.Session {
+	return []*session.Session{c.session}
 }
 
 func (c *counter) Get(ctx context.Context) (int64, error) {
-	response, err := c.session.DoQuery(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	response, err := c.session.DoQuery(ctx, getOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewCounterServiceClient(conn)
 		request := &api.GetRequest{
 			Header: header,
@@ -98,7 +129,7 @@ func (c *counter) Get(ctx context.Context) (int64, error) {
 }
 
 func (c *counter) Set(ctx context.Context, value int64) error {
-	_, err := c.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	_, err := c.session.DoCommand(ctx, setOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewCounterServiceClient(conn)
 		request := &api.SetRequest{
 			Header: header,
@@ -114,7 +145,40 @@ func (c *counter) Set(ctx context.Context, value int64) error {
 }
 
 func (c *counter) Increment(ctx context.Context, delta int64) (int64, error) {
-	response, err := c.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	response, err := c.increment(ctx, incrementOp, delta)
+	if err != nil {
+		return 0, err
+	}
+	return response.NextValue, nil
+}
+
+func (c *counter) GetAndIncrement(ctx context.Context) (int64, error) {
+	return c.GetAndAdd(ctx, 1)
+}
+
+func (c *counter) IncrementAndGet(ctx context.Context) (int64, error) {
+	return c.AddAndGet(ctx, 1)
+}
+
+func (c *counter) GetAndAdd(ctx context.Context, delta int64) (int64, error) {
+	response, err := c.increment(ctx, getAndAddOp, delta)
+	if err != nil {
+		return 0, err
+	}
+	return response.PreviousValue, nil
+}
+
+func (c *counter) AddAndGet(ctx context.Context, delta int64) (int64, error) {
+	response, err := c.increment(ctx, addAndGetOp, delta)
+	if err != nil {
+		return 0, err
+	}
+	return response.NextValue, nil
+}
+
+// increment adds the given delta to the counter in a single command, returning both the previous and updated values
+func (c *counter) increment(ctx context.Context, op string, delta int64) (*api.IncrementResponse, error) {
+	response, err := c.session.DoCommand(ctx, op, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewCounterServiceClient(conn)
 		request := &api.IncrementRequest{
 			Header: header,
@@ -127,13 +191,13 @@ func (c *counter) Increment(ctx context.Context, delta int64) (int64, error) {
 		return response.Header, response, nil
 	})
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	return response.(*api.IncrementResponse).NextValue, nil
+	return response.(*api.IncrementResponse), nil
 }
 
 func (c *counter) Decrement(ctx context.Context, delta int64) (int64, error) {
-	response, err := c.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	response, err := c.session.DoCommand(ctx, decrementOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewCounterServiceClient(conn)
 		request := &api.DecrementRequest{
 			Header: header,
@@ -151,6 +215,25 @@ func (c *counter) Decrement(ctx context.Context, delta int64) (int64, error) {
 	return response.(*api.DecrementResponse).NextValue, nil
 }
 
+func (c *counter) Reset(ctx context.Context) (int64, error) {
+	response, err := c.session.DoCommand(ctx, resetOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		client := api.NewCounterServiceClient(conn)
+		request := &api.SetRequest{
+			Header: header,
+			Value:  0,
+		}
+		response, err := client.Set(ctx, request)
+		if err != nil {
+			return nil, nil, err
+		}
+		return response.Header, response, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return response.(*api.SetResponse).PreviousValue, nil
+}
+
 func (c *counter) Close() error {
 	return c.session.Close()
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/atomix/go-client/pkg/client/session"
 	"github.com/atomix/go-client/pkg/client/test"
 	"github.com/stretchr/testify/assert"
+	"sync"
 	"testing"
 	"time"
 )
@@ -31,6 +32,8 @@ func TestCounterOperations(t *testing.T) {
 	counter, err := New(context.TODO(), name, conns, session.WithTimeout(5*time.Second))
 	assert.NoError(t, err)
 	assert.NotNil(t, counter)
+	assert.Equal(t, Type, counter.Metadata().Type)
+	assert.Equal(t, "test", counter.Metadata().Name.Group)
 
 	value, err := counter.Get(context.TODO())
 	assert.NoError(t, err)
@@ -62,7 +65,19 @@ func TestCounterOperations(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, int64(-10), value)
 
-	value, err = counter.Increment(context.TODO(), 20)
+	value, err = counter.Reset(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-10), value)
+
+	value, err = counter.Get(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), value)
+
+	value, err = counter.Reset(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), value)
+
+	value, err = counter.Increment(context.TODO(), 10)
 	assert.NoError(t, err)
 	assert.Equal(t, int64(10), value)
 
@@ -97,3 +112,89 @@ func TestCounterOperations(t *testing.T) {
 
 	test.StopTestPartitions(partitions)
 }
+
+func TestCounterFetchAndAdd(t *testing.T) {
+	conns, partitions := test.StartTestPartitions(3)
+
+	name := primitive.NewName("default", "test", "default", "fetch-and-add")
+	counter, err := New(context.TODO(), name, conns, session.WithTimeout(5*time.Second))
+	assert.NoError(t, err)
+
+	value, err := counter.GetAndIncrement(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), value)
+
+	value, err = counter.IncrementAndGet(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), value)
+
+	value, err = counter.GetAndAdd(context.TODO(), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), value)
+
+	value, err = counter.AddAndGet(context.TODO(), -12)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), value)
+
+	// Allocate IDs concurrently from multiple counter instances
+	const goroutines = 10
+	const allocations = 20
+	ids := make(chan int64, goroutines*allocations)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < goroutines; i++ {
+		allocator, err := New(context.TODO(), name, conns, session.WithTimeout(5*time.Second))
+		assert.NoError(t, err)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer allocator.Close()
+			for j := 0; j < allocations; j++ {
+				id, err := allocator.GetAndIncrement(context.TODO())
+				assert.NoError(t, err)
+				ids <- id
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	allocated := make(map[int64]bool)
+	for id := range ids {
+		assert.False(t, allocated[id], "duplicate ID %d", id)
+		allocated[id] = true
+	}
+	for id := int64(0); id < goroutines*allocations; id++ {
+		assert.True(t, allocated[id], "missing ID %d", id)
+	}
+
+	err = counter.Delete()
+	assert.NoError(t, err)
+
+	test.StopTestPartitions(partitions)
+}
+
+func TestCounterOperationNames(t *testing.T) {
+	conns, partitions := test.StartTestPartitions(1)
+
+	// Operations are reported to tracers and metrics by their primitive operation names
+	recorder := &test.OperationRecorder{}
+	name := primitive.NewName("default", "test", "default", "test")
+	counter, err := New(context.TODO(), name, conns, append(recorder.Options(), session.WithTimeout(5*time.Second))...)
+	assert.NoError(t, err)
+
+	_, err = counter.Get(context.TODO())
+	assert.NoError(t, err)
+	err = counter.Set(context.TODO(), 1)
+	assert.NoError(t, err)
+	_, err = counter.Increment(context.TODO(), 1)
+	assert.NoError(t, err)
+
+	for _, op := range []string{"counter.Get", "counter.Set", "counter.Increment"} {
+		assert.Contains(t, recorder.Spans(), op)
+		assert.Contains(t, recorder.Observed(), op)
+	}
+
+	err = counter.Close()
+	assert.NoError(t, err)
+	test.StopTestPartitions(partitions)
+}
@@ -0,0 +1,104 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package counter
+
+import (
+	"context"
+	"github.com/atomix/go-client/pkg/client/primitive"
+	"github.com/atomix/go-client/pkg/client/session"
+	"sync"
+)
+
+// NewFake returns an in-memory Counter for testing code that depends on Counter
+// The fake implements the semantics of Counter without a session or a server. The fake has no sessions.
+func NewFake(name primitive.Name) Counter {
+	return &fakeCounter{
+		Base: primitive.NewBase(Type, name),
+	}
+}
+
+// fakeCounter is an in-memory implementation of Counter
+type fakeCounter struct {
+	primitive.Base
+	value int64
+	mu    sync.Mutex
+}
+
+func (c *fakeCounter) Sessions() []*session.Session {
+	return nil
+}
+
+func (c *fakeCounter) Get(ctx context.Context) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.value, nil
+}
+
+func (c *fakeCounter) Set(ctx context.Context, value int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.value = value
+	return nil
+}
+
+func (c *fakeCounter) Increment(ctx context.Context, delta int64) (int64, error) {
+	return c.AddAndGet(ctx, delta)
+}
+
+func (c *fakeCounter) Decrement(ctx context.Context, delta int64) (int64, error) {
+	return c.AddAndGet(ctx, -delta)
+}
+
+func (c *fakeCounter) Reset(ctx context.Context) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	prev := c.value
+	c.value = 0
+	return prev, nil
+}
+
+func (c *fakeCounter) GetAndIncrement(ctx context.Context) (int64, error) {
+	return c.GetAndAdd(ctx, 1)
+}
+
+func (c *fakeCounter) IncrementAndGet(ctx context.Context) (int64, error) {
+	return c.AddAndGet(ctx, 1)
+}
+
+func (c *fakeCounter) GetAndAdd(ctx context.Context, delta int64) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	prev := c.value
+	c.value += delta
+	return prev, nil
+}
+
+func (c *fakeCounter) AddAndGet(ctx context.Context, delta int64) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.value += delta
+	return c.value, nil
+}
+
+func (c *fakeCounter) Close() error {
+	return nil
+}
+
+func (c *fakeCounter) Delete() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.value = 0
+	return nil
+}
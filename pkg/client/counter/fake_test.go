@@ -0,0 +1,60 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package counter
+
+import (
+	"context"
+	"github.com/atomix/go-client/pkg/client/primitive"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFakeCounter(t *testing.T) {
+	counter := NewFake(primitive.NewName("default", "test", "default", "test"))
+	assert.Equal(t, Type, counter.Metadata().Type)
+	assert.Len(t, counter.Sessions(), 0)
+
+	value, err := counter.Get(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), value)
+
+	assert.NoError(t, counter.Set(context.TODO(), 5))
+	value, err = counter.Increment(context.TODO(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), value)
+	value, err = counter.Decrement(context.TODO(), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-3), value)
+
+	value, err = counter.GetAndIncrement(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-3), value)
+	value, err = counter.IncrementAndGet(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-1), value)
+	value, err = counter.GetAndAdd(context.TODO(), 11)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-1), value)
+	value, err = counter.AddAndGet(context.TODO(), 5)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(15), value)
+
+	value, err = counter.Reset(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(15), value)
+	value, err = counter.Get(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), value)
+}
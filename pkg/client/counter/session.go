@@ -18,12 +18,17 @@ import (
 	"context"
 	api "github.com/atomix/api/proto/atomix/counter"
 	"github.com/atomix/api/proto/atomix/headers"
+	"github.com/atomix/go-client/pkg/client/primitive"
 	"github.com/atomix/go-client/pkg/client/session"
 	"google.golang.org/grpc"
 )
 
 type sessionHandler struct{}
 
+func (m *sessionHandler) Type() primitive.Type {
+	return Type
+}
+
 func (m *sessionHandler) Create(ctx context.Context, s *session.Session) error {
 	return s.DoCreate(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		request := &api.CreateRequest{
@@ -20,7 +20,6 @@ import (
 	"github.com/atomix/api/proto/atomix/headers"
 	"github.com/atomix/go-client/pkg/client/primitive"
 	"github.com/atomix/go-client/pkg/client/session"
-	"github.com/atomix/go-client/pkg/client/util"
 	"github.com/atomix/go-client/pkg/client/util/net"
 	"google.golang.org/grpc"
 )
@@ -28,6 +27,17 @@ import (
 // Type is the election type
 const Type primitive.Type = "Election"
 
+// Operation names used to identify election operations in traces and metrics
+const (
+	getTermOp = "election.GetTerm"
+	enterOp   = "election.Enter"
+	leaveOp   = "election.Leave"
+	anointOp  = "election.Anoint"
+	promoteOp = "election.Promote"
+	evictOp   = "election.Evict"
+	watchOp   = "election.Watch"
+)
+
 // Client provides an API for creating Elections
 type Client interface {
 	// GetElection gets the Election instance of the given name
@@ -38,6 +48,9 @@ type Client interface {
 type Election interface {
 	primitive.Primitive
 
+	// Sessions returns the sessions used by the primitive, one for each partition in which it's stored
+	Sessions() []*session.Session
+
 	// ID returns the ID of the instance of the election
 	ID() string
 
@@ -107,7 +120,7 @@ type Event struct {
 
 // New creates a new election primitive
 func New(ctx context.Context, name primitive.Name, partitions []net.Address, opts ...session.Option) (Election, error) {
-	i, err := util.GetPartitionIndex(name.Name, len(partitions))
+	i, err := session.GetPartitioner(opts...)(name.Name, len(partitions))
 	if err != nil {
 		return nil, err
 	}
@@ -118,19 +131,19 @@ func New(ctx context.Context, name primitive.Name, partitions []net.Address, opt
 	}
 
 	return &election{
-		name:    name,
+		Base:    primitive.NewBase(Type, name),
 		session: sess,
 	}, nil
 }
 
 // election is the default single-partition implementation of Election
 type election struct {
-	name    primitive.Name
+	primitive.Base
 	session *session.Session
 }
 
-func (e *election) Name() primitive.Name {
-	return e.name
+func (e *election) Sessions() []*session.Session {
+	return []*session.Session{e.session}
 }
 
 func (e *election) ID() string {
@@ -138,7 +151,7 @@ func (e *election) ID() string {
 }
 
 func (e *election) GetTerm(ctx context.Context) (*Term, error) {
-	response, err := e.session.DoQuery(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	response, err := e.session.DoQuery(ctx, getTermOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewLeaderElectionServiceClient(conn)
 		request := &api.GetTermRequest{
 			Header: header,
@@ -156,7 +169,7 @@ func (e *election) GetTerm(ctx context.Context) (*Term, error) {
 }
 
 func (e *election) Enter(ctx context.Context) (*Term, error) {
-	response, err := e.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	response, err := e.session.DoCommand(ctx, enterOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewLeaderElectionServiceClient(conn)
 		request := &api.EnterRequest{
 			Header:      header,
@@ -175,7 +188,7 @@ func (e *election) Enter(ctx context.Context) (*Term, error) {
 }
 
 func (e *election) Leave(ctx context.Context) (*Term, error) {
-	response, err := e.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	response, err := e.session.DoCommand(ctx, leaveOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewLeaderElectionServiceClient(conn)
 		request := &api.WithdrawRequest{
 			Header:      header,
@@ -194,7 +207,7 @@ func (e *election) Leave(ctx context.Context) (*Term, error) {
 }
 
 func (e *election) Anoint(ctx context.Context, id string) (*Term, error) {
-	response, err := e.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	response, err := e.session.DoCommand(ctx, anointOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewLeaderElectionServiceClient(conn)
 		request := &api.AnointRequest{
 			Header:      header,
@@ -213,7 +226,7 @@ func (e *election) Anoint(ctx context.Context, id string) (*Term, error) {
 }
 
 func (e *election) Promote(ctx context.Context, id string) (*Term, error) {
-	response, err := e.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	response, err := e.session.DoCommand(ctx, promoteOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewLeaderElectionServiceClient(conn)
 		request := &api.PromoteRequest{
 			Header:      header,
@@ -232,7 +245,7 @@ func (e *election) Promote(ctx context.Context, id string) (*Term, error) {
 }
 
 func (e *election) Evict(ctx context.Context, id string) (*Term, error) {
-	response, err := e.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	response, err := e.session.DoCommand(ctx, evictOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewLeaderElectionServiceClient(conn)
 		request := &api.EvictRequest{
 			Header:      header,
@@ -251,7 +264,7 @@ func (e *election) Evict(ctx context.Context, id string) (*Term, error) {
 }
 
 func (e *election) Watch(ctx context.Context, ch chan<- *Event) error {
-	stream, err := e.session.DoCommandStream(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
+	stream, err := e.session.DoCommandStream(ctx, watchOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
 		client := api.NewLeaderElectionServiceClient(conn)
 		request := &api.EventRequest{
 			Header: header,
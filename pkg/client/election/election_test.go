@@ -257,3 +257,29 @@ func TestElectionOperations(t *testing.T) {
 
 	test.StopTestPartitions(partitions)
 }
+
+func TestElectionOperationNames(t *testing.T) {
+	conns, partitions := test.StartTestPartitions(1)
+
+	// Operations are reported to tracers and metrics by their primitive operation names
+	recorder := &test.OperationRecorder{}
+	name := primitive.NewName("default", "test", "default", "test")
+	election, err := New(context.TODO(), name, conns, append(recorder.Options(), session.WithTimeout(5*time.Second))...)
+	assert.NoError(t, err)
+
+	_, err = election.Enter(context.TODO())
+	assert.NoError(t, err)
+	_, err = election.GetTerm(context.TODO())
+	assert.NoError(t, err)
+	_, err = election.Leave(context.TODO())
+	assert.NoError(t, err)
+
+	for _, op := range []string{"election.Enter", "election.GetTerm", "election.Leave"} {
+		assert.Contains(t, recorder.Spans(), op)
+		assert.Contains(t, recorder.Observed(), op)
+	}
+
+	err = election.Close()
+	assert.NoError(t, err)
+	test.StopTestPartitions(partitions)
+}
@@ -22,7 +22,6 @@ import (
 	api "github.com/atomix/api/proto/atomix/indexedmap"
 	"github.com/atomix/go-client/pkg/client/primitive"
 	"github.com/atomix/go-client/pkg/client/session"
-	"github.com/atomix/go-client/pkg/client/util"
 	"github.com/atomix/go-client/pkg/client/util/net"
 	"google.golang.org/grpc"
 	"time"
@@ -31,6 +30,31 @@ import (
 // Type is the indexedmap type
 const Type primitive.Type = "IndexedMap"
 
+// Operation names used to identify indexedmap operations in traces and metrics
+const (
+	appendOp       = "indexedmap.Append"
+	putOp          = "indexedmap.Put"
+	setOp          = "indexedmap.Set"
+	getOp          = "indexedmap.Get"
+	getIndexOp     = "indexedmap.GetIndex"
+	firstIndexOp   = "indexedmap.FirstIndex"
+	lastIndexOp    = "indexedmap.LastIndex"
+	prevIndexOp    = "indexedmap.PrevIndex"
+	nextIndexOp    = "indexedmap.NextIndex"
+	firstEntryOp   = "indexedmap.FirstEntry"
+	lastEntryOp    = "indexedmap.LastEntry"
+	prevEntryOp    = "indexedmap.PrevEntry"
+	nextEntryOp    = "indexedmap.NextEntry"
+	replaceOp      = "indexedmap.Replace"
+	replaceIndexOp = "indexedmap.ReplaceIndex"
+	removeOp       = "indexedmap.Remove"
+	removeIndexOp  = "indexedmap.RemoveIndex"
+	lenOp          = "indexedmap.Len"
+	clearOp        = "indexedmap.Clear"
+	entriesOp      = "indexedmap.Entries"
+	watchOp        = "indexedmap.Watch"
+)
+
 // Index is the index of an entry
 type Index uint64
 
@@ -47,6 +71,9 @@ type Client interface {
 type IndexedMap interface {
 	primitive.Primitive
 
+	// Sessions returns the sessions used by the primitive, one for each partition in which it's stored
+	Sessions() []*session.Session
+
 	// Append appends the given key/value to the map
 	Append(ctx context.Context, key string, value []byte) (*Entry, error)
 
@@ -170,7 +197,7 @@ type Event struct {
 
 // New creates a new IndexedMap primitive
 func New(ctx context.Context, name primitive.Name, partitions []net.Address, opts ...session.Option) (IndexedMap, error) {
-	i, err := util.GetPartitionIndex(name.Name, len(partitions))
+	i, err := session.GetPartitioner(opts...)(name.Name, len(partitions))
 	if err != nil {
 		return nil, err
 	}
@@ -184,23 +211,23 @@ func newIndexedMap(ctx context.Context, name primitive.Name, address net.Address
 		return nil, err
 	}
 	return &indexedMap{
-		name:    name,
+		Base:    primitive.NewBase(Type, name),
 		session: sess,
 	}, nil
 }
 
 // indexedMap is the default single-partition implementation of Map
 type indexedMap struct {
-	name    primitive.Name
+	primitive.Base
 	session *session.Session
 }
 
-func (m *indexedMap) Name() primitive.Name {
-	return m.name
+func (m *indexedMap) Sessions() []*session.Session {
+	return []*session.Session{m.session}
 }
 
 func (m *indexedMap) Append(ctx context.Context, key string, value []byte) (*Entry, error) {
-	r, err := m.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	r, err := m.session.DoCommand(ctx, appendOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewIndexedMapServiceClient(conn)
 		request := &api.PutRequest{
 			Header:  header,
@@ -243,7 +270,7 @@ func (m *indexedMap) Append(ctx context.Context, key string, value []byte) (*Ent
 }
 
 func (m *indexedMap) Put(ctx context.Context, key string, value []byte) (*Entry, error) {
-	r, err := m.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	r, err := m.session.DoCommand(ctx, putOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewIndexedMapServiceClient(conn)
 		request := &api.PutRequest{
 			Header: header,
@@ -285,7 +312,7 @@ func (m *indexedMap) Put(ctx context.Context, key string, value []byte) (*Entry,
 }
 
 func (m *indexedMap) Set(ctx context.Context, index Index, key string, value []byte, opts ...SetOption) (*Entry, error) {
-	r, err := m.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	r, err := m.session.DoCommand(ctx, setOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewIndexedMapServiceClient(conn)
 		request := &api.PutRequest{
 			Header: header,
@@ -334,7 +361,7 @@ func (m *indexedMap) Set(ctx context.Context, index Index, key string, value []b
 }
 
 func (m *indexedMap) Get(ctx context.Context, key string, opts ...GetOption) (*Entry, error) {
-	r, err := m.session.DoQuery(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	r, err := m.session.DoQuery(ctx, getOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewIndexedMapServiceClient(conn)
 		request := &api.GetRequest{
 			Header: header,
@@ -371,7 +398,7 @@ func (m *indexedMap) Get(ctx context.Context, key string, opts ...GetOption) (*E
 }
 
 func (m *indexedMap) GetIndex(ctx context.Context, index Index, opts ...GetOption) (*Entry, error) {
-	r, err := m.session.DoQuery(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	r, err := m.session.DoQuery(ctx, getIndexOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewIndexedMapServiceClient(conn)
 		request := &api.GetRequest{
 			Header: header,
@@ -408,7 +435,7 @@ func (m *indexedMap) GetIndex(ctx context.Context, index Index, opts ...GetOptio
 }
 
 func (m *indexedMap) FirstIndex(ctx context.Context) (Index, error) {
-	r, err := m.session.DoQuery(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	r, err := m.session.DoQuery(ctx, firstIndexOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewIndexedMapServiceClient(conn)
 		request := &api.FirstEntryRequest{
 			Header: header,
@@ -431,7 +458,7 @@ func (m *indexedMap) FirstIndex(ctx context.Context) (Index, error) {
 }
 
 func (m *indexedMap) LastIndex(ctx context.Context) (Index, error) {
-	r, err := m.session.DoQuery(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	r, err := m.session.DoQuery(ctx, lastIndexOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewIndexedMapServiceClient(conn)
 		request := &api.LastEntryRequest{
 			Header: header,
@@ -454,7 +481,7 @@ func (m *indexedMap) LastIndex(ctx context.Context) (Index, error) {
 }
 
 func (m *indexedMap) PrevIndex(ctx context.Context, index Index) (Index, error) {
-	r, err := m.session.DoQuery(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	r, err := m.session.DoQuery(ctx, prevIndexOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewIndexedMapServiceClient(conn)
 		request := &api.PrevEntryRequest{
 			Header: header,
@@ -478,7 +505,7 @@ func (m *indexedMap) PrevIndex(ctx context.Context, index Index) (Index, error)
 }
 
 func (m *indexedMap) NextIndex(ctx context.Context, index Index) (Index, error) {
-	r, err := m.session.DoQuery(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	r, err := m.session.DoQuery(ctx, nextIndexOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewIndexedMapServiceClient(conn)
 		request := &api.NextEntryRequest{
 			Header: header,
@@ -502,7 +529,7 @@ func (m *indexedMap) NextIndex(ctx context.Context, index Index) (Index, error)
 }
 
 func (m *indexedMap) FirstEntry(ctx context.Context) (*Entry, error) {
-	r, err := m.session.DoQuery(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	r, err := m.session.DoQuery(ctx, firstEntryOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewIndexedMapServiceClient(conn)
 		request := &api.FirstEntryRequest{
 			Header: header,
@@ -532,7 +559,7 @@ func (m *indexedMap) FirstEntry(ctx context.Context) (*Entry, error) {
 }
 
 func (m *indexedMap) LastEntry(ctx context.Context) (*Entry, error) {
-	r, err := m.session.DoQuery(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	r, err := m.session.DoQuery(ctx, lastEntryOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewIndexedMapServiceClient(conn)
 		request := &api.LastEntryRequest{
 			Header: header,
@@ -562,7 +589,7 @@ func (m *indexedMap) LastEntry(ctx context.Context) (*Entry, error) {
 }
 
 func (m *indexedMap) PrevEntry(ctx context.Context, index Index) (*Entry, error) {
-	r, err := m.session.DoQuery(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	r, err := m.session.DoQuery(ctx, prevEntryOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewIndexedMapServiceClient(conn)
 		request := &api.PrevEntryRequest{
 			Header: header,
@@ -593,7 +620,7 @@ func (m *indexedMap) PrevEntry(ctx context.Context, index Index) (*Entry, error)
 }
 
 func (m *indexedMap) NextEntry(ctx context.Context, index Index) (*Entry, error) {
-	r, err := m.session.DoQuery(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	r, err := m.session.DoQuery(ctx, nextEntryOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewIndexedMapServiceClient(conn)
 		request := &api.NextEntryRequest{
 			Header: header,
@@ -624,7 +651,7 @@ func (m *indexedMap) NextEntry(ctx context.Context, index Index) (*Entry, error)
 }
 
 func (m *indexedMap) Replace(ctx context.Context, key string, value []byte, opts ...ReplaceOption) (*Entry, error) {
-	r, err := m.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	r, err := m.session.DoCommand(ctx, replaceOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewIndexedMapServiceClient(conn)
 		request := &api.ReplaceRequest{
 			Header:   header,
@@ -665,7 +692,7 @@ func (m *indexedMap) Replace(ctx context.Context, key string, value []byte, opts
 }
 
 func (m *indexedMap) ReplaceIndex(ctx context.Context, index Index, value []byte, opts ...ReplaceOption) (*Entry, error) {
-	r, err := m.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	r, err := m.session.DoCommand(ctx, replaceIndexOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewIndexedMapServiceClient(conn)
 		request := &api.ReplaceRequest{
 			Header:   header,
@@ -706,7 +733,7 @@ func (m *indexedMap) ReplaceIndex(ctx context.Context, index Index, value []byte
 }
 
 func (m *indexedMap) Remove(ctx context.Context, key string, opts ...RemoveOption) (*Entry, error) {
-	r, err := m.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	r, err := m.session.DoCommand(ctx, removeOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewIndexedMapServiceClient(conn)
 		request := &api.RemoveRequest{
 			Header: header,
@@ -746,7 +773,7 @@ func (m *indexedMap) Remove(ctx context.Context, key string, opts ...RemoveOptio
 }
 
 func (m *indexedMap) RemoveIndex(ctx context.Context, index Index, opts ...RemoveOption) (*Entry, error) {
-	r, err := m.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	r, err := m.session.DoCommand(ctx, removeIndexOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewIndexedMapServiceClient(conn)
 		request := &api.RemoveRequest{
 			Header: header,
@@ -786,7 +813,7 @@ func (m *indexedMap) RemoveIndex(ctx context.Context, index Index, opts ...Remov
 }
 
 func (m *indexedMap) Len(ctx context.Context) (int, error) {
-	response, err := m.session.DoQuery(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	response, err := m.session.DoQuery(ctx, lenOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewIndexedMapServiceClient(conn)
 		request := &api.SizeRequest{
 			Header: header,
@@ -804,7 +831,7 @@ func (m *indexedMap) Len(ctx context.Context) (int, error) {
 }
 
 func (m *indexedMap) Clear(ctx context.Context) error {
-	_, err := m.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	_, err := m.session.DoCommand(ctx, clearOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewIndexedMapServiceClient(conn)
 		request := &api.ClearRequest{
 			Header: header,
@@ -819,7 +846,7 @@ func (m *indexedMap) Clear(ctx context.Context) error {
 }
 
 func (m *indexedMap) Entries(ctx context.Context, ch chan<- *Entry) error {
-	stream, err := m.session.DoQueryStream(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
+	stream, err := m.session.DoQueryStream(ctx, entriesOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
 		client := api.NewIndexedMapServiceClient(conn)
 		request := &api.EntriesRequest{
 			Header: header,
@@ -854,7 +881,7 @@ func (m *indexedMap) Entries(ctx context.Context, ch chan<- *Entry) error {
 }
 
 func (m *indexedMap) Watch(ctx context.Context, ch chan<- *Event, opts ...WatchOption) error {
-	stream, err := m.session.DoCommandStream(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
+	stream, err := m.session.DoCommandStream(ctx, watchOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
 		client := api.NewIndexedMapServiceClient(conn)
 		request := &api.EventRequest{
 			Header: header,
@@ -295,3 +295,29 @@ func TestIndexedMapStreams(t *testing.T) {
 
 	test.StopTestPartitions(partitions)
 }
+
+func TestIndexedMapOperationNames(t *testing.T) {
+	conns, partitions := test.StartTestPartitions(1)
+
+	// Operations are reported to tracers and metrics by their primitive operation names
+	recorder := &test.OperationRecorder{}
+	name := primitive.NewName("default", "test", "default", "test")
+	_map, err := New(context.TODO(), name, conns, append(recorder.Options(), session.WithTimeout(5*time.Second))...)
+	assert.NoError(t, err)
+
+	_, err = _map.Put(context.TODO(), "foo", []byte("bar"))
+	assert.NoError(t, err)
+	_, err = _map.Get(context.TODO(), "foo")
+	assert.NoError(t, err)
+	_, err = _map.Len(context.TODO())
+	assert.NoError(t, err)
+
+	for _, op := range []string{"indexedmap.Put", "indexedmap.Get", "indexedmap.Len"} {
+		assert.Contains(t, recorder.Spans(), op)
+		assert.Contains(t, recorder.Observed(), op)
+	}
+
+	err = _map.Close()
+	assert.NoError(t, err)
+	test.StopTestPartitions(partitions)
+}
@@ -21,11 +21,17 @@ import (
 	api "github.com/atomix/api/proto/atomix/leader"
 	"github.com/atomix/go-client/pkg/client/primitive"
 	"github.com/atomix/go-client/pkg/client/session"
-	"github.com/atomix/go-client/pkg/client/util"
 	"github.com/atomix/go-client/pkg/client/util/net"
 	"google.golang.org/grpc"
 )
 
+// Operation names used to identify leader operations in traces and metrics
+const (
+	getOp   = "leader.Get"
+	joinOp  = "leader.Join"
+	watchOp = "leader.Watch"
+)
+
 // Type is the leader latch type
 const Type primitive.Type = "LeaderLatch"
 
@@ -39,6 +45,9 @@ type Client interface {
 type Latch interface {
 	primitive.Primitive
 
+	// Sessions returns the sessions used by the primitive, one for each partition in which it's stored
+	Sessions() []*session.Session
+
 	// ID returns the ID of the instance of the latch
 	ID() string
 
@@ -99,7 +108,7 @@ type Event struct {
 
 // New creates a new latch primitive
 func New(ctx context.Context, name primitive.Name, partitions []net.Address, opts ...session.Option) (Latch, error) {
-	i, err := util.GetPartitionIndex(name.Name, len(partitions))
+	i, err := session.GetPartitioner(opts...)(name.Name, len(partitions))
 	if err != nil {
 		return nil, err
 	}
@@ -110,19 +119,19 @@ func New(ctx context.Context, name primitive.Name, partitions []net.Address, opt
 	}
 
 	return &latch{
-		name:    name,
+		Base:    primitive.NewBase(Type, name),
 		session: sess,
 	}, nil
 }
 
 // latch is the default single-partition implementation of Latch
 type latch struct {
-	name    primitive.Name
+	primitive.Base
 	session *session.Session
 }
 
-func (e *latch) Name() primitive.Name {
-	return e.name
+func (e *latch) Sessions() []*session.Session {
+	return []*session.Session{e.session}
 }
 
 func (e *latch) ID() string {
@@ -130,7 +139,7 @@ func (e *latch) ID() string {
 }
 
 func (e *latch) Get(ctx context.Context) (*Leadership, error) {
-	response, err := e.session.DoQuery(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	response, err := e.session.DoQuery(ctx, getOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewLeaderLatchServiceClient(conn)
 		request := &api.GetRequest{
 			Header: header,
@@ -148,7 +157,7 @@ func (e *latch) Get(ctx context.Context) (*Leadership, error) {
 }
 
 func (e *latch) Join(ctx context.Context) (*Leadership, error) {
-	response, err := e.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	response, err := e.session.DoCommand(ctx, joinOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewLeaderLatchServiceClient(conn)
 		request := &api.LatchRequest{
 			Header:        header,
@@ -188,7 +197,7 @@ func (e *latch) Latch(ctx context.Context) (*Leadership, error) {
 }
 
 func (e *latch) Watch(ctx context.Context, ch chan<- *Event) error {
-	stream, err := e.session.DoCommandStream(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
+	stream, err := e.session.DoCommandStream(ctx, watchOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
 		client := api.NewLeaderLatchServiceClient(conn)
 		request := &api.EventRequest{
 			Header: header,
@@ -146,3 +146,27 @@ func TestLatchOperations(t *testing.T) {
 
 	test.StopTestPartitions(partitions)
 }
+
+func TestLatchOperationNames(t *testing.T) {
+	conns, partitions := test.StartTestPartitions(1)
+
+	// Operations are reported to tracers and metrics by their primitive operation names
+	recorder := &test.OperationRecorder{}
+	name := primitive.NewName("default", "test", "default", "test")
+	latch, err := New(context.TODO(), name, conns, append(recorder.Options(), session.WithTimeout(5*time.Second))...)
+	assert.NoError(t, err)
+
+	_, err = latch.Join(context.TODO())
+	assert.NoError(t, err)
+	_, err = latch.Get(context.TODO())
+	assert.NoError(t, err)
+
+	for _, op := range []string{"leader.Join", "leader.Get"} {
+		assert.Contains(t, recorder.Spans(), op)
+		assert.Contains(t, recorder.Observed(), op)
+	}
+
+	err = latch.Close()
+	assert.NoError(t, err)
+	test.StopTestPartitions(partitions)
+}
@@ -22,7 +22,6 @@ import (
 	api "github.com/atomix/api/proto/atomix/list"
 	"github.com/atomix/go-client/pkg/client/primitive"
 	"github.com/atomix/go-client/pkg/client/session"
-	"github.com/atomix/go-client/pkg/client/util"
 	"github.com/atomix/go-client/pkg/client/util/net"
 	"google.golang.org/grpc"
 )
@@ -30,6 +29,19 @@ import (
 // Type is the list type
 const Type primitive.Type = "List"
 
+// Operation names used to identify list operations in traces and metrics
+const (
+	appendOp = "list.Append"
+	insertOp = "list.Insert"
+	setOp    = "list.Set"
+	getOp    = "list.Get"
+	removeOp = "list.Remove"
+	lenOp    = "list.Len"
+	itemsOp  = "list.Items"
+	watchOp  = "list.Watch"
+	clearOp  = "list.Clear"
+)
+
 // Client provides an API for creating Lists
 type Client interface {
 	// GetList gets the List instance of the given name
@@ -42,6 +54,9 @@ type Client interface {
 type List interface {
 	primitive.Primitive
 
+	// Sessions returns the sessions used by the primitive, one for each partition in which it's stored
+	Sessions() []*session.Session
+
 	// Append pushes a value on to the end of the list
 	Append(ctx context.Context, value []byte) error
 
@@ -111,7 +126,7 @@ type Event struct {
 
 // New creates a new list primitive
 func New(ctx context.Context, name primitive.Name, partitions []net.Address, opts ...session.Option) (List, error) {
-	i, err := util.GetPartitionIndex(name.Name, len(partitions))
+	i, err := session.GetPartitioner(opts...)(name.Name, len(partitions))
 	if err != nil {
 		return nil, err
 	}
@@ -125,23 +140,23 @@ func newList(ctx context.Context, name primitive.Name, address net.Address, opts
 		return nil, err
 	}
 	return &list{
-		name:    name,
+		Base:    primitive.NewBase(Type, name),
 		session: sess,
 	}, nil
 }
 
 // list is the single partition implementation of List
 type list struct {
-	name    primitive.Name
+	primitive.Base
 	session *session.Session
 }
 
-func (l *list) Name() primitive.Name {
-	return l.name
+func (l *list) Sessions() []*session.Session {
+	return []*session.Session{l.session}
 }
 
 func (l *list) Append(ctx context.Context, value []byte) error {
-	_, err := l.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	_, err := l.session.DoCommand(ctx, appendOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewListServiceClient(conn)
 		request := &api.AppendRequest{
 			Header: header,
@@ -157,7 +172,7 @@ func (l *list) Append(ctx context.Context, value []byte) error {
 }
 
 func (l *list) Insert(ctx context.Context, index int, value []byte) error {
-	response, err := l.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	response, err := l.session.DoCommand(ctx, insertOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewListServiceClient(conn)
 		request := &api.InsertRequest{
 			Header: header,
@@ -183,7 +198,7 @@ func (l *list) Insert(ctx context.Context, index int, value []byte) error {
 }
 
 func (l *list) Set(ctx context.Context, index int, value []byte) error {
-	response, err := l.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	response, err := l.session.DoCommand(ctx, setOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewListServiceClient(conn)
 		request := &api.SetRequest{
 			Header: header,
@@ -209,7 +224,7 @@ func (l *list) Set(ctx context.Context, index int, value []byte) error {
 }
 
 func (l *list) Get(ctx context.Context, index int) ([]byte, error) {
-	r, err := l.session.DoQuery(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	r, err := l.session.DoQuery(ctx, getOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewListServiceClient(conn)
 		request := &api.GetRequest{
 			Header: header,
@@ -235,7 +250,7 @@ func (l *list) Get(ctx context.Context, index int) ([]byte, error) {
 }
 
 func (l *list) Remove(ctx context.Context, index int) ([]byte, error) {
-	r, err := l.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	r, err := l.session.DoCommand(ctx, removeOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewListServiceClient(conn)
 		request := &api.RemoveRequest{
 			Header: header,
@@ -261,7 +276,7 @@ func (l *list) Remove(ctx context.Context, index int) ([]byte, error) {
 }
 
 func (l *list) Len(ctx context.Context) (int, error) {
-	response, err := l.session.DoQuery(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	response, err := l.session.DoQuery(ctx, lenOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewListServiceClient(conn)
 		request := &api.SizeRequest{
 			Header: header,
@@ -279,7 +294,7 @@ func (l *list) Len(ctx context.Context) (int, error) {
 }
 
 func (l *list) Items(ctx context.Context, ch chan<- []byte) error {
-	stream, err := l.session.DoQueryStream(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
+	stream, err := l.session.DoQueryStream(ctx, itemsOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
 		client := api.NewListServiceClient(conn)
 		request := &api.IterateRequest{
 			Header: header,
@@ -309,7 +324,7 @@ func (l *list) Items(ctx context.Context, ch chan<- []byte) error {
 }
 
 func (l *list) Watch(ctx context.Context, ch chan<- *Event, opts ...WatchOption) error {
-	stream, err := l.session.DoCommandStream(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
+	stream, err := l.session.DoCommandStream(ctx, watchOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
 		client := api.NewListServiceClient(conn)
 		request := &api.EventRequest{
 			Header: header,
@@ -381,7 +396,7 @@ func (l *list) SliceTo(ctx context.Context, to int) (List, error) {
 }
 
 func (l *list) Clear(ctx context.Context) error {
-	_, err := l.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	_, err := l.session.DoCommand(ctx, clearOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewListServiceClient(conn)
 		request := &api.ClearRequest{
 			Header: header,
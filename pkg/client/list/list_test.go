@@ -216,3 +216,29 @@ func TestListOperations(t *testing.T) {
 
 	test.StopTestPartitions(partitions)
 }
+
+func TestListOperationNames(t *testing.T) {
+	conns, partitions := test.StartTestPartitions(1)
+
+	// Operations are reported to tracers and metrics by their primitive operation names
+	recorder := &test.OperationRecorder{}
+	name := primitive.NewName("default", "test", "default", "test")
+	list, err := New(context.TODO(), name, conns, append(recorder.Options(), session.WithTimeout(5*time.Second))...)
+	assert.NoError(t, err)
+
+	err = list.Append(context.TODO(), []byte("foo"))
+	assert.NoError(t, err)
+	_, err = list.Get(context.TODO(), 0)
+	assert.NoError(t, err)
+	_, err = list.Len(context.TODO())
+	assert.NoError(t, err)
+
+	for _, op := range []string{"list.Append", "list.Get", "list.Len"} {
+		assert.Contains(t, recorder.Spans(), op)
+		assert.Contains(t, recorder.Observed(), op)
+	}
+
+	err = list.Close()
+	assert.NoError(t, err)
+	test.StopTestPartitions(partitions)
+}
@@ -18,12 +18,17 @@ import (
 	"context"
 	"github.com/atomix/api/proto/atomix/headers"
 	api "github.com/atomix/api/proto/atomix/list"
+	"github.com/atomix/go-client/pkg/client/primitive"
 	"github.com/atomix/go-client/pkg/client/session"
 	"google.golang.org/grpc"
 )
 
 type sessionHandler struct{}
 
+func (h *sessionHandler) Type() primitive.Type {
+	return Type
+}
+
 func (h *sessionHandler) Create(ctx context.Context, s *session.Session) error {
 	return s.DoCreate(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		request := &api.CreateRequest{
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"github.com/atomix/go-client/pkg/client/primitive"
+	"github.com/atomix/go-client/pkg/client/session"
 )
 
 // slicedList is a slice of a list
@@ -31,6 +32,14 @@ func (l *slicedList) Name() primitive.Name {
 	return l.list.Name()
 }
 
+func (l *slicedList) Metadata() primitive.Metadata {
+	return l.list.Metadata()
+}
+
+func (l *slicedList) Sessions() []*session.Session {
+	return l.list.Sessions()
+}
+
 func (l *slicedList) inRangeIndex(index int) bool {
 	return (l.from == nil || index >= *l.from) && (l.to == nil || index < *l.to)
 }
@@ -20,7 +20,6 @@ import (
 	api "github.com/atomix/api/proto/atomix/lock"
 	"github.com/atomix/go-client/pkg/client/primitive"
 	"github.com/atomix/go-client/pkg/client/session"
-	"github.com/atomix/go-client/pkg/client/util"
 	"github.com/atomix/go-client/pkg/client/util/net"
 	"google.golang.org/grpc"
 )
@@ -28,6 +27,13 @@ import (
 // Type is the lock type
 const Type primitive.Type = "Lock"
 
+// Operation names used to identify lock operations in traces and metrics
+const (
+	lockOp     = "lock.Lock"
+	unlockOp   = "lock.Unlock"
+	isLockedOp = "lock.IsLocked"
+)
+
 // Client provides an API for creating Locks
 type Client interface {
 	// GetLock gets the Lock instance of the given name
@@ -38,6 +44,9 @@ type Client interface {
 type Lock interface {
 	primitive.Primitive
 
+	// Sessions returns the sessions used by the primitive, one for each partition in which it's stored
+	Sessions() []*session.Session
+
 	// Lock acquires the lock
 	Lock(ctx context.Context, opts ...LockOption) (uint64, error)
 
@@ -51,7 +60,7 @@ type Lock interface {
 // New creates a new Lock primitive for the given partitions
 // The lock will be created in one of the given partitions.
 func New(ctx context.Context, name primitive.Name, partitions []net.Address, opts ...session.Option) (Lock, error) {
-	i, err := util.GetPartitionIndex(name.Name, len(partitions))
+	i, err := session.GetPartitioner(opts...)(name.Name, len(partitions))
 	if err != nil {
 		return nil, err
 	}
@@ -65,23 +74,23 @@ func newLock(ctx context.Context, name primitive.Name, address net.Address, opts
 		return nil, err
 	}
 	return &lock{
-		name:    name,
+		Base:    primitive.NewBase(Type, name),
 		session: sess,
 	}, nil
 }
 
 // lock is the single partition implementation of Lock
 type lock struct {
-	name    primitive.Name
+	primitive.Base
 	session *session.Session
 }
 
-func (l *lock) Name() primitive.Name {
-	return l.name
+func (l *lock) Sessions() []*session.Session {
+	return []*session.Session{l.session}
 }
 
 func (l *lock) Lock(ctx context.Context, opts ...LockOption) (uint64, error) {
-	response, err := l.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	response, err := l.session.DoCommand(ctx, lockOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewLockServiceClient(conn)
 		request := &api.LockRequest{
 			Header: header,
@@ -105,7 +114,7 @@ func (l *lock) Lock(ctx context.Context, opts ...LockOption) (uint64, error) {
 }
 
 func (l *lock) Unlock(ctx context.Context, opts ...UnlockOption) (bool, error) {
-	response, err := l.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	response, err := l.session.DoCommand(ctx, unlockOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewLockServiceClient(conn)
 		request := &api.UnlockRequest{
 			Header: header,
@@ -129,7 +138,7 @@ func (l *lock) Unlock(ctx context.Context, opts ...UnlockOption) (bool, error) {
 }
 
 func (l *lock) IsLocked(ctx context.Context, opts ...IsLockedOption) (bool, error) {
-	response, err := l.session.DoQuery(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	response, err := l.session.DoQuery(ctx, isLockedOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewLockServiceClient(conn)
 		request := &api.IsLockedRequest{
 			Header: header,
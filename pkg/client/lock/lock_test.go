@@ -95,3 +95,29 @@ func TestLock(t *testing.T) {
 
 	test.StopTestPartitions(partitions)
 }
+
+func TestLockOperationNames(t *testing.T) {
+	conns, partitions := test.StartTestPartitions(1)
+
+	// Operations are reported to tracers and metrics by their primitive operation names
+	recorder := &test.OperationRecorder{}
+	name := primitive.NewName("default", "test", "default", "test")
+	l, err := New(context.TODO(), name, conns, append(recorder.Options(), session.WithTimeout(5*time.Second))...)
+	assert.NoError(t, err)
+
+	_, err = l.Lock(context.TODO())
+	assert.NoError(t, err)
+	_, err = l.IsLocked(context.TODO())
+	assert.NoError(t, err)
+	_, err = l.Unlock(context.TODO())
+	assert.NoError(t, err)
+
+	for _, op := range []string{"lock.Lock", "lock.IsLocked", "lock.Unlock"} {
+		assert.Contains(t, recorder.Spans(), op)
+		assert.Contains(t, recorder.Observed(), op)
+	}
+
+	err = l.Close()
+	assert.NoError(t, err)
+	test.StopTestPartitions(partitions)
+}
@@ -38,6 +38,9 @@ type Client interface {
 type Map interface {
 	primitive.Primitive
 
+	// Sessions returns the sessions used by the primitive, one for each partition in which it's stored
+	Sessions() []*session.Session
+
 	// Put sets a key/value pair in the map
 	Put(ctx context.Context, key string, value []byte, opts ...PutOption) (*Entry, error)
 
@@ -47,6 +50,10 @@ type Map interface {
 	// Remove removes a key from the map
 	Remove(ctx context.Context, key string, opts ...RemoveOption) (*Entry, error)
 
+	// RemoveIfValue removes a key from the map if its current value matches the given value
+	// Returns true if the key was removed and false if the key was not present or its value did not match.
+	RemoveIfValue(ctx context.Context, key string, expect []byte) (bool, error)
+
 	// Len returns the number of entries in the map
 	Len(ctx context.Context) (int, error)
 
@@ -115,8 +122,11 @@ type Event struct {
 
 // New creates a new partitioned Map
 func New(ctx context.Context, name primitive.Name, partitions []net.Address, opts ...session.Option) (Map, error) {
-	results, err := util.ExecuteOrderedAsync(len(partitions), func(i int) (interface{}, error) {
+	// If any partition fails to open, close the partitions that were opened before returning the error
+	results, err := util.ExecuteOrderedAsyncContext(ctx, len(partitions), func(ctx context.Context, i int) (interface{}, error) {
 		return newPartition(ctx, partitions[i], name, opts...)
+	}, func(result interface{}) {
+		result.(Map).Close()
 	})
 	if err != nil {
 		return nil, err
@@ -128,23 +138,29 @@ func New(ctx context.Context, name primitive.Name, partitions []net.Address, opt
 	}
 
 	return &_map{
-		name:       name,
-		partitions: maps,
+		Base:        primitive.NewBase(Type, name),
+		partitions:  maps,
+		partitioner: session.GetPartitioner(opts...),
 	}, nil
 }
 
 // _map is the default single-partition implementation of Map
 type _map struct {
-	name       primitive.Name
-	partitions []Map
+	primitive.Base
+	partitions  []Map
+	partitioner util.Partitioner
 }
 
-func (m *_map) Name() primitive.Name {
-	return m.name
+func (m *_map) Sessions() []*session.Session {
+	sessions := make([]*session.Session, 0, len(m.partitions))
+	for _, partition := range m.partitions {
+		sessions = append(sessions, partition.Sessions()...)
+	}
+	return sessions
 }
 
 func (m *_map) getPartition(key string) (Map, error) {
-	i, err := util.GetPartitionIndex(key, len(m.partitions))
+	i, err := m.partitioner(key, len(m.partitions))
 	if err != nil {
 		return nil, err
 	}
@@ -175,6 +191,14 @@ func (m *_map) Remove(ctx context.Context, key string, opts ...RemoveOption) (*E
 	return session.Remove(ctx, key, opts...)
 }
 
+func (m *_map) RemoveIfValue(ctx context.Context, key string, expect []byte) (bool, error) {
+	session, err := m.getPartition(key)
+	if err != nil {
+		return false, err
+	}
+	return session.RemoveIfValue(ctx, key, expect)
+}
+
 func (m *_map) Len(ctx context.Context) (int, error) {
 	results, err := util.ExecuteAsync(len(m.partitions), func(i int) (interface{}, error) {
 		return m.partitions[i].Len(ctx)
@@ -30,6 +30,7 @@ func TestMapOperations(t *testing.T) {
 	name := primitive.NewName("default", "test", "default", "test")
 	_map, err := New(context.TODO(), name, conns, session.WithTimeout(5*time.Second))
 	assert.NoError(t, err)
+	assert.Len(t, _map.Sessions(), 3)
 
 	kv, err := _map.Get(context.Background(), "foo")
 	assert.NoError(t, err)
@@ -115,6 +116,59 @@ func TestMapOperations(t *testing.T) {
 	test.StopTestPartitions(partitions)
 }
 
+func TestMapRemoveIfValue(t *testing.T) {
+	conns, partitions := test.StartTestPartitions(3)
+
+	name := primitive.NewName("default", "test", "default", "test")
+	_map, err := New(context.TODO(), name, conns, session.WithTimeout(5*time.Second))
+	assert.NoError(t, err)
+
+	removed, err := _map.RemoveIfValue(context.Background(), "foo", []byte("bar"))
+	assert.NoError(t, err)
+	assert.False(t, removed)
+
+	_, err = _map.Put(context.Background(), "foo", []byte("bar"))
+	assert.NoError(t, err)
+
+	removed, err = _map.RemoveIfValue(context.Background(), "foo", []byte("baz"))
+	assert.NoError(t, err)
+	assert.False(t, removed)
+
+	kv, err := _map.Get(context.Background(), "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", string(kv.Value))
+
+	removed, err = _map.RemoveIfValue(context.Background(), "foo", []byte("bar"))
+	assert.NoError(t, err)
+	assert.True(t, removed)
+
+	kv, err = _map.Get(context.Background(), "foo")
+	assert.NoError(t, err)
+	assert.Nil(t, kv)
+
+	// Only one of many concurrent removes of the same value succeeds
+	_, err = _map.Put(context.Background(), "foo", []byte("bar"))
+	assert.NoError(t, err)
+
+	results := make(chan bool)
+	for i := 0; i < 10; i++ {
+		go func() {
+			removed, err := _map.RemoveIfValue(context.Background(), "foo", []byte("bar"))
+			assert.NoError(t, err)
+			results <- removed
+		}()
+	}
+	succeeded := 0
+	for i := 0; i < 10; i++ {
+		if <-results {
+			succeeded++
+		}
+	}
+	assert.Equal(t, 1, succeeded)
+
+	test.StopTestPartitions(partitions)
+}
+
 func TestMapStreams(t *testing.T) {
 	conns, partitions := test.StartTestPartitions(3)
 
@@ -220,3 +274,29 @@ func TestMapStreams(t *testing.T) {
 
 	test.StopTestPartitions(partitions)
 }
+
+func TestMapOperationNames(t *testing.T) {
+	conns, partitions := test.StartTestPartitions(1)
+
+	// Operations are reported to tracers and metrics by their primitive operation names
+	recorder := &test.OperationRecorder{}
+	name := primitive.NewName("default", "test", "default", "test")
+	_map, err := New(context.TODO(), name, conns, append(recorder.Options(), session.WithTimeout(5*time.Second))...)
+	assert.NoError(t, err)
+
+	_, err = _map.Put(context.TODO(), "foo", []byte("bar"))
+	assert.NoError(t, err)
+	_, err = _map.Get(context.TODO(), "foo")
+	assert.NoError(t, err)
+	_, err = _map.Len(context.TODO())
+	assert.NoError(t, err)
+
+	for _, op := range []string{"map.Put", "map.Get", "map.Len"} {
+		assert.Contains(t, recorder.Spans(), op)
+		assert.Contains(t, recorder.Observed(), op)
+	}
+
+	err = _map.Close()
+	assert.NoError(t, err)
+	test.StopTestPartitions(partitions)
+}
@@ -15,6 +15,7 @@
 package _map //nolint:golint
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"github.com/atomix/api/proto/atomix/headers"
@@ -25,28 +26,42 @@ import (
 	"google.golang.org/grpc"
 )
 
+// Operation names used to identify map operations in traces and metrics
+const (
+	putOp     = "map.Put"
+	getOp     = "map.Get"
+	removeOp  = "map.Remove"
+	lenOp     = "map.Len"
+	clearOp   = "map.Clear"
+	entriesOp = "map.Entries"
+	watchOp   = "map.Watch"
+)
+
+// errWriteConditionFailed is returned when the version condition of a write is not met
+var errWriteConditionFailed = errors.New("write condition failed")
+
 func newPartition(ctx context.Context, address net.Address, name primitive.Name, opts ...session.Option) (Map, error) {
 	sess, err := session.New(ctx, name, address, &sessionHandler{}, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return &mapPartition{
-		name:    name,
+		Base:    primitive.NewBase(Type, name),
 		session: sess,
 	}, nil
 }
 
 type mapPartition struct {
-	name    primitive.Name
+	primitive.Base
 	session *session.Session
 }
 
-func (m *mapPartition) Name() primitive.Name {
-	return m.name
+func (m *mapPartition) Sessions() []*session.Session {
+	return []*session.Session{m.session}
 }
 
 func (m *mapPartition) Put(ctx context.Context, key string, value []byte, opts ...PutOption) (*Entry, error) {
-	r, err := m.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	r, err := m.session.DoCommand(ctx, putOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewMapServiceClient(conn)
 		request := &api.PutRequest{
 			Header: header,
@@ -77,7 +92,7 @@ func (m *mapPartition) Put(ctx context.Context, key string, value []byte, opts .
 			Version: int64(response.Header.Index),
 		}, nil
 	} else if response.Status == api.ResponseStatus_PRECONDITION_FAILED {
-		return nil, errors.New("write condition failed")
+		return nil, errWriteConditionFailed
 	} else if response.Status == api.ResponseStatus_WRITE_LOCK {
 		return nil, errors.New("write lock failed")
 	} else {
@@ -92,7 +107,7 @@ func (m *mapPartition) Put(ctx context.Context, key string, value []byte, opts .
 }
 
 func (m *mapPartition) Get(ctx context.Context, key string, opts ...GetOption) (*Entry, error) {
-	r, err := m.session.DoQuery(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	r, err := m.session.DoQuery(ctx, getOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewMapServiceClient(conn)
 		request := &api.GetRequest{
 			Header: header,
@@ -128,7 +143,7 @@ func (m *mapPartition) Get(ctx context.Context, key string, opts ...GetOption) (
 }
 
 func (m *mapPartition) Remove(ctx context.Context, key string, opts ...RemoveOption) (*Entry, error) {
-	r, err := m.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	r, err := m.session.DoCommand(ctx, removeOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewMapServiceClient(conn)
 		request := &api.RemoveRequest{
 			Header: header,
@@ -158,7 +173,7 @@ func (m *mapPartition) Remove(ctx context.Context, key string, opts ...RemoveOpt
 			Version: response.PreviousVersion,
 		}, nil
 	} else if response.Status == api.ResponseStatus_PRECONDITION_FAILED {
-		return nil, errors.New("write condition failed")
+		return nil, errWriteConditionFailed
 	} else if response.Status == api.ResponseStatus_WRITE_LOCK {
 		return nil, errors.New("write lock failed")
 	} else {
@@ -166,8 +181,29 @@ func (m *mapPartition) Remove(ctx context.Context, key string, opts ...RemoveOpt
 	}
 }
 
+// RemoveIfValue is implemented as an optimistic loop since the map service only supports version conditions on
+// removes. The entry is read and, if its value matches, removed conditionally on the version read, retrying if the
+// entry was concurrently updated.
+func (m *mapPartition) RemoveIfValue(ctx context.Context, key string, expect []byte) (bool, error) {
+	for {
+		entry, err := m.Get(ctx, key)
+		if err != nil {
+			return false, err
+		} else if entry == nil || !bytes.Equal(entry.Value, expect) {
+			return false, nil
+		}
+
+		removed, err := m.Remove(ctx, key, IfVersion(entry.Version))
+		if err == nil {
+			return removed != nil, nil
+		} else if err != errWriteConditionFailed {
+			return false, err
+		}
+	}
+}
+
 func (m *mapPartition) Len(ctx context.Context) (int, error) {
-	response, err := m.session.DoQuery(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	response, err := m.session.DoQuery(ctx, lenOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewMapServiceClient(conn)
 		request := &api.SizeRequest{
 			Header: header,
@@ -185,7 +221,7 @@ func (m *mapPartition) Len(ctx context.Context) (int, error) {
 }
 
 func (m *mapPartition) Clear(ctx context.Context) error {
-	_, err := m.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	_, err := m.session.DoCommand(ctx, clearOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewMapServiceClient(conn)
 		request := &api.ClearRequest{
 			Header: header,
@@ -200,7 +236,7 @@ func (m *mapPartition) Clear(ctx context.Context) error {
 }
 
 func (m *mapPartition) Entries(ctx context.Context, ch chan<- *Entry) error {
-	stream, err := m.session.DoQueryStream(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
+	stream, err := m.session.DoQueryStream(ctx, entriesOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
 		client := api.NewMapServiceClient(conn)
 		request := &api.EntriesRequest{
 			Header: header,
@@ -234,7 +270,7 @@ func (m *mapPartition) Entries(ctx context.Context, ch chan<- *Entry) error {
 }
 
 func (m *mapPartition) Watch(ctx context.Context, ch chan<- *Event, opts ...WatchOption) error {
-	stream, err := m.session.DoCommandStream(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
+	stream, err := m.session.DoCommandStream(ctx, watchOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
 		client := api.NewMapServiceClient(conn)
 		request := &api.EventRequest{
 			Header: header,
@@ -0,0 +1,128 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package _map //nolint:golint
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// ErrUnknownIndex is returned by GetBy when the view has no index with the given name
+var ErrUnknownIndex = errors.New("unknown index")
+
+// IndexFunc derives the keys under which an entry is indexed from the entry's value
+type IndexFunc func(value []byte) []string
+
+// IndexedMapView is a client-side view of a Map with secondary indexes
+// The view maintains a copy of the map's entries along with an index of each entry's derived keys, kept up to date
+// by watching the map. Lookups are served from the view and reflect changes to the map once the corresponding
+// events have been received, so the view may briefly lag behind the map. The view holds every entry in the map
+// in memory, plus one reference per index key.
+type IndexedMapView struct {
+	Map
+	indexes map[string]IndexFunc
+	entries map[string]*Entry
+	index   map[string]map[string]map[string]bool
+	mu      sync.RWMutex
+}
+
+// NewIndexedMapView creates a view of the given map indexed by the given index functions
+// The view is kept up to date until the given context is canceled.
+func NewIndexedMapView(ctx context.Context, m Map, indexes map[string]IndexFunc) (*IndexedMapView, error) {
+	view := &IndexedMapView{
+		Map:     m,
+		indexes: indexes,
+		entries: make(map[string]*Entry),
+		index:   make(map[string]map[string]map[string]bool),
+	}
+	for name := range indexes {
+		view.index[name] = make(map[string]map[string]bool)
+	}
+
+	ch := make(chan *Event)
+	if err := m.Watch(ctx, ch, WithReplay()); err != nil {
+		return nil, err
+	}
+	go func() {
+		for event := range ch {
+			view.update(event)
+		}
+	}()
+	return view, nil
+}
+
+// update applies a map event to the view
+func (v *IndexedMapView) update(event *Event) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if old, ok := v.entries[event.Entry.Key]; ok {
+		v.unindex(old)
+		delete(v.entries, old.Key)
+	}
+	if event.Type != EventRemoved {
+		v.entries[event.Entry.Key] = event.Entry
+		v.reindex(event.Entry)
+	}
+}
+
+// reindex adds the entry to each index
+func (v *IndexedMapView) reindex(entry *Entry) {
+	for name, f := range v.indexes {
+		for _, indexKey := range f(entry.Value) {
+			keys, ok := v.index[name][indexKey]
+			if !ok {
+				keys = make(map[string]bool)
+				v.index[name][indexKey] = keys
+			}
+			keys[entry.Key] = true
+		}
+	}
+}
+
+// unindex removes the entry from each index
+func (v *IndexedMapView) unindex(entry *Entry) {
+	for name, f := range v.indexes {
+		for _, indexKey := range f(entry.Value) {
+			keys := v.index[name][indexKey]
+			delete(keys, entry.Key)
+			if len(keys) == 0 {
+				delete(v.index[name], indexKey)
+			}
+		}
+	}
+}
+
+// GetBy gets the entries indexed under the given key in the named index
+// Entries are returned in order of their primary keys.
+func (v *IndexedMapView) GetBy(indexName string, indexKey string) ([]*Entry, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	index, ok := v.index[indexName]
+	if !ok {
+		return nil, ErrUnknownIndex
+	}
+
+	keys := index[indexKey]
+	entries := make([]*Entry, 0, len(keys))
+	for key := range keys {
+		entries = append(entries, v.entries[key])
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+	return entries, nil
+}
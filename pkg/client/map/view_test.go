@@ -0,0 +1,94 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package _map //nolint:golint
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+// watchMap is a Map that replays the events written to it to watchers
+type watchMap struct {
+	Map
+	events chan *Event
+}
+
+func (m *watchMap) Watch(ctx context.Context, ch chan<- *Event, opts ...WatchOption) error {
+	go func() {
+		for event := range m.events {
+			ch <- event
+		}
+		close(ch)
+	}()
+	return nil
+}
+
+// getKeys waits for the view to index the given number of entries under the given key and returns their keys
+func getKeys(t *testing.T, view *IndexedMapView, indexName string, indexKey string, n int) []string {
+	var entries []*Entry
+	var err error
+	for i := 0; i < 100; i++ {
+		entries, err = view.GetBy(indexName, indexKey)
+		assert.NoError(t, err)
+		if len(entries) == n {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	keys := make([]string, len(entries))
+	for i, entry := range entries {
+		keys[i] = entry.Key
+	}
+	return keys
+}
+
+func TestIndexedMapView(t *testing.T) {
+	m := &watchMap{
+		events: make(chan *Event),
+	}
+
+	// Index entries by the city in "name:city" values
+	view, err := NewIndexedMapView(context.TODO(), m, map[string]IndexFunc{
+		"city": func(value []byte) []string {
+			return []string{strings.Split(string(value), ":")[1]}
+		},
+	})
+	assert.NoError(t, err)
+
+	_, err = view.GetBy("country", "us")
+	assert.Equal(t, ErrUnknownIndex, err)
+
+	m.events <- &Event{Type: EventNone, Entry: &Entry{Key: "1", Value: []byte("alice:paris")}}
+	m.events <- &Event{Type: EventInserted, Entry: &Entry{Key: "2", Value: []byte("bob:paris")}}
+	m.events <- &Event{Type: EventInserted, Entry: &Entry{Key: "3", Value: []byte("carol:rome")}}
+	assert.Equal(t, []string{"1", "2"}, getKeys(t, view, "city", "paris", 2))
+	assert.Equal(t, []string{"3"}, getKeys(t, view, "city", "rome", 1))
+
+	m.events <- &Event{Type: EventUpdated, Entry: &Entry{Key: "2", Value: []byte("bob:rome")}}
+	assert.Equal(t, []string{"2", "3"}, getKeys(t, view, "city", "rome", 2))
+	assert.Equal(t, []string{"1"}, getKeys(t, view, "city", "paris", 1))
+
+	m.events <- &Event{Type: EventRemoved, Entry: &Entry{Key: "1", Value: []byte("alice:paris")}}
+	assert.Equal(t, []string{}, getKeys(t, view, "city", "paris", 0))
+	assert.Equal(t, []string{"2", "3"}, getKeys(t, view, "city", "rome", 2))
+
+	entries, err := view.GetBy("city", "rome")
+	assert.NoError(t, err)
+	assert.Equal(t, "bob:rome", string(entries[0].Value))
+	close(m.events)
+}
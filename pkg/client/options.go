@@ -14,7 +14,10 @@
 
 package client
 
-import "os"
+import (
+	"github.com/atomix/go-client/pkg/client/session"
+	"os"
+)
 
 func applyOptions(opts ...Option) *options {
 	options := &options{
@@ -28,8 +31,10 @@ func applyOptions(opts ...Option) *options {
 }
 
 type options struct {
-	application string
-	namespace   string
+	application     string
+	namespace       string
+	maxWatchStreams int
+	watchPolicy     session.WatchPolicy
 }
 
 // Option provides a client option
@@ -62,3 +67,31 @@ func (o *namespaceOption) apply(options *options) {
 func WithNamespace(namespace string) Option {
 	return &namespaceOption{namespace: namespace}
 }
+
+type maxWatchStreamsOption struct {
+	max int
+}
+
+func (o *maxWatchStreamsOption) apply(options *options) {
+	options.maxWatchStreams = o.max
+}
+
+// WithMaxWatchStreams limits the number of concurrent watch streams opened by the client's primitives
+// By default the number of watch streams is unbounded. When the limit is reached, new watches fail with
+// session.ErrTooManyWatches unless the session.WatchBlock policy is configured with WithWatchPolicy.
+func WithMaxWatchStreams(max int) Option {
+	return &maxWatchStreamsOption{max: max}
+}
+
+type watchPolicyOption struct {
+	policy session.WatchPolicy
+}
+
+func (o *watchPolicyOption) apply(options *options) {
+	options.watchPolicy = o.policy
+}
+
+// WithWatchPolicy configures the policy applied to new watches when the watch stream limit is reached
+func WithWatchPolicy(policy session.WatchPolicy) Option {
+	return &watchPolicyOption{policy: policy}
+}
@@ -15,6 +15,7 @@
 package client
 
 import (
+	"github.com/atomix/go-client/pkg/client/session"
 	"github.com/stretchr/testify/assert"
 	"os"
 	"testing"
@@ -29,4 +30,9 @@ func TestOptions(t *testing.T) {
 	options = applyOptions(WithNamespace("foo"), WithApplication("bar"))
 	assert.Equal(t, "foo", options.namespace)
 	assert.Equal(t, "bar", options.application)
+	assert.Equal(t, 0, options.maxWatchStreams)
+	assert.Equal(t, session.WatchReject, options.watchPolicy)
+	options = applyOptions(WithMaxWatchStreams(10), WithWatchPolicy(session.WatchBlock))
+	assert.Equal(t, 10, options.maxWatchStreams)
+	assert.Equal(t, session.WatchBlock, options.watchPolicy)
 }
@@ -14,7 +14,13 @@
 
 package primitive
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound is returned by Delete when the primitive does not exist in the cluster
+var ErrNotFound = errors.New("primitive not found")
 
 // Type is the type of a primitive
 type Type string
@@ -50,9 +56,48 @@ type Primitive interface {
 	// Name returns the fully namespaced primitive name
 	Name() Name
 
+	// Metadata returns the primitive's type and name
+	Metadata() Metadata
+
 	// Close closes the primitive
 	Close() error
 
 	// Delete deletes the primitive state from the cluster
+	// If the primitive does not exist, e.g. because it was already deleted by another client, ErrNotFound is returned.
 	Delete() error
 }
+
+// Metadata describes a primitive
+type Metadata struct {
+	// Type is the type of the primitive
+	Type Type
+
+	// Name is the fully namespaced primitive name, including the partition group in which it's stored
+	Name Name
+}
+
+// NewBase returns a Base for a primitive of the given type and name
+func NewBase(t Type, name Name) Base {
+	return Base{
+		metadata: Metadata{
+			Type: t,
+			Name: name,
+		},
+	}
+}
+
+// Base implements the descriptive methods of Primitive
+// Primitive implementations embed Base so that methods added to describe primitives don't break them.
+type Base struct {
+	metadata Metadata
+}
+
+// Name returns the fully namespaced primitive name
+func (b Base) Name() Name {
+	return b.metadata.Name
+}
+
+// Metadata returns the primitive's type and name
+func (b Base) Metadata() Metadata {
+	return b.metadata
+}
@@ -0,0 +1,29 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package primitive
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBase(t *testing.T) {
+	name := NewName("default", "raft", "test", "foo")
+	base := NewBase("Value", name)
+	assert.Equal(t, name, base.Name())
+	assert.Equal(t, Type("Value"), base.Metadata().Type)
+	assert.Equal(t, "raft", base.Metadata().Name.Group)
+	assert.Equal(t, "default", base.Metadata().Name.Namespace)
+}
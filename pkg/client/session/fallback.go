@@ -0,0 +1,75 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"github.com/atomix/api/proto/atomix/headers"
+	"github.com/atomix/go-client/pkg/client/util/net"
+	"google.golang.org/grpc"
+	"sync/atomic"
+)
+
+// WithReplicas returns a session Option to configure replicas that may serve reads while the leader is unavailable
+// The replicas are keyed by partition address, so the same option can be passed to the sessions of all partitions
+// of a primitive. Replicas are only used by queries sent with DoQueryWithFallback, which primitives only do for
+// reads that explicitly opt into stale reads. Other queries and all commands are always sent to the leader.
+func WithReplicas(replicas map[net.Address][]net.Address) Option {
+	return replicasOption{replicas: replicas}
+}
+
+type replicasOption struct {
+	replicas map[net.Address][]net.Address
+}
+
+func (o replicasOption) prepare(options *options) {
+	options.replicas = o.replicas
+}
+
+// DoQueryWithFallback sends a session query request, falling back to the session's replicas if the leader is unavailable
+// If the query fails because the leader is unavailable and replicas are configured with WithReplicas, the query is
+// sent to each replica in turn with relaxed consistency: the replica serves the query from its current state
+// without waiting to catch up with the session's writes, so the result may not reflect preceding writes on the
+// session. Returns a bool indicating whether the response was served by a replica and may be stale. If no replica
+// can serve the query, the query is retried against the leader as with DoQuery.
+func (s *Session) DoQueryWithFallback(ctx context.Context, name string, f func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error)) (interface{}, bool, error) {
+	return s.doQuery(ctx, name, f, true)
+}
+
+// fallbackQuery sends a query to the session's replicas in order until one serves it
+// The query is sent without the session's last index so replicas that are behind the leader can serve it.
+func (s *Session) fallbackQuery(
+	ctx context.Context,
+	header *headers.RequestHeader,
+	f func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error)) (*headers.ResponseHeader, interface{}, bool) {
+	staleHeader := &headers.RequestHeader{
+		Name:      header.Name,
+		SessionID: header.SessionID,
+		RequestID: header.RequestID,
+	}
+	for _, replica := range s.replicas {
+		conn, err := replica.Connect()
+		if err != nil {
+			continue
+		}
+		responseHeader, response, err := f(ctx, conn, staleHeader)
+		if err == nil && responseHeader.Status == headers.ResponseStatus_OK {
+			atomic.AddUint64(&s.stats.FallbackQueries, 1)
+			s.logger.Warn("Leader unavailable, query served by replica", s.fields("replica", replica.Address)...)
+			return responseHeader, response, true
+		}
+	}
+	return nil, nil, false
+}
@@ -0,0 +1,98 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// ErrResponseDropped is returned by operations whose response was dropped by a fault Injector
+var ErrResponseDropped = errors.New("response dropped by fault injector")
+
+// Fault is a fault to inject into a session operation
+type Fault struct {
+	// Err is returned in place of sending the operation's request if set
+	Err error
+
+	// Latency is added before the operation's request is sent
+	Latency time.Duration
+
+	// Drop indicates the operation's request is sent but its response is dropped
+	Drop bool
+}
+
+// Injector injects faults into session operations for chaos testing
+// Fault injection is intended for testing only and should never be configured in production.
+type Injector interface {
+	// Inject returns the fault to inject into the given operation
+	Inject(op Operation) Fault
+}
+
+// RandomInjector is an Injector that injects faults with the configured probabilities
+// Probabilities are in the range [0, 1] and are evaluated independently for each operation.
+type RandomInjector struct {
+	// ErrorProbability is the probability of failing an operation with Error
+	ErrorProbability float64
+
+	// Error is the error returned by failed operations
+	Error error
+
+	// LatencyProbability is the probability of adding Latency to an operation
+	LatencyProbability float64
+
+	// Latency is the latency added to delayed operations
+	Latency time.Duration
+
+	// DropProbability is the probability of dropping an operation's response
+	DropProbability float64
+}
+
+// Inject returns a random fault for the given operation
+func (i *RandomInjector) Inject(op Operation) Fault {
+	fault := Fault{}
+	if i.ErrorProbability > 0 && rand.Float64() < i.ErrorProbability {
+		fault.Err = i.Error
+	}
+	if i.LatencyProbability > 0 && rand.Float64() < i.LatencyProbability {
+		fault.Latency = i.Latency
+	}
+	if i.DropProbability > 0 && rand.Float64() < i.DropProbability {
+		fault.Drop = true
+	}
+	return fault
+}
+
+// injectFault applies the fault chosen by the session's Injector to the operation before its request is sent
+// Returns a bool indicating whether the operation's response should be dropped.
+func (s *Session) injectFault(ctx context.Context, op *operation) (bool, error) {
+	if s.injector == nil {
+		return false, nil
+	}
+	fault := s.injector.Inject(op.Operation)
+	if fault.Latency > 0 {
+		select {
+		case <-time.After(fault.Latency):
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+	if fault.Err != nil {
+		return false, fault.Err
+	}
+	return fault.Drop, nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"github.com/atomix/go-client/pkg/client/util/net"
+	"time"
+)
+
+// State is the state of a session
+type State string
+
+const (
+	// StateOpen indicates the session is open and its last keep-alive succeeded
+	StateOpen State = "open"
+
+	// StateSuspended indicates the session's last keep-alive failed
+	// A suspended session is reopened by the next successful keep-alive unless it has expired.
+	StateSuspended State = "suspended"
+
+	// StateClosed indicates the session was closed or deleted
+	StateClosed State = "closed"
+)
+
+// Partition returns the address of the partition to which the session is bound
+func (s *Session) Partition() net.Address {
+	return s.conns.Address
+}
+
+// State returns the current state of the session
+func (s *Session) State() State {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state
+}
+
+// Healthy returns whether the session is open
+func (s *Session) Healthy() bool {
+	return s.State() == StateOpen
+}
+
+// Ping sends a keep-alive for the session and returns the round-trip latency
+func (s *Session) Ping(ctx context.Context) (time.Duration, error) {
+	start := time.Now()
+	err := s.handler.KeepAlive(ctx, s)
+	s.recordKeepAlive(err)
+	return time.Since(start), err
+}
+
+// keepAlive sends a keep-alive for the session, bounded by the session timeout
+func (s *Session) keepAlive() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.Timeout)
+	defer cancel()
+	err := s.handler.KeepAlive(ctx, s)
+	if err != nil {
+		s.logger.Warn("Session keep-alive failed", s.fields("error", err)...)
+	} else {
+		s.logger.Debug("Kept session alive", s.fields()...)
+	}
+	s.recordKeepAlive(err)
+}
+
+// recordKeepAlive updates the state of the session with the result of a keep-alive
+func (s *Session) recordKeepAlive(err error) {
+	s.setState(func(state State) State {
+		if state == StateClosed {
+			return state
+		} else if err != nil {
+			return StateSuspended
+		}
+		return StateOpen
+	})
+}
+
+// setState updates the state of the session with the given function
+func (s *Session) setState(f func(State) State) {
+	s.mu.Lock()
+	s.state = f(s.state)
+	s.mu.Unlock()
+}
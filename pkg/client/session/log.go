@@ -0,0 +1,80 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"github.com/atomix/go-client/pkg/client/util"
+)
+
+// Logger logs session lifecycle events
+// Fields are alternating keys and values, e.g. "leader", "localhost:5678". An adapter for the standard library
+// log/slog package is provided by the logging package.
+type Logger interface {
+	// Debug logs a debug message with the given fields
+	Debug(msg string, fields ...interface{})
+
+	// Info logs an info message with the given fields
+	Info(msg string, fields ...interface{})
+
+	// Warn logs a warning message with the given fields
+	Warn(msg string, fields ...interface{})
+
+	// Error logs an error message with the given fields
+	Error(msg string, fields ...interface{})
+}
+
+// WithLogger returns a session Option to log session lifecycle events to the given Logger
+// By default nothing is logged. A nil Logger is treated as the default.
+func WithLogger(logger Logger) Option {
+	return loggerOption{logger: logger}
+}
+
+type loggerOption struct {
+	logger Logger
+}
+
+func (o loggerOption) prepare(options *options) {
+	if o.logger == nil {
+		options.logger = noopLogger{}
+		return
+	}
+	options.logger = o.logger
+}
+
+// noopLogger is the Logger used when logging is not configured
+type noopLogger struct{}
+
+func (noopLogger) Debug(msg string, fields ...interface{}) {}
+
+func (noopLogger) Info(msg string, fields ...interface{}) {}
+
+func (noopLogger) Warn(msg string, fields ...interface{}) {}
+
+func (noopLogger) Error(msg string, fields ...interface{}) {}
+
+// fields returns the given fields prefixed with the fields identifying the session
+func (s *Session) fields(fields ...interface{}) []interface{} {
+	return append([]interface{}{"session", s.ID, "primitive", s.Name.Namespace + "." + s.Name.Name}, fields...)
+}
+
+// streamFields returns the given fields prefixed with the fields identifying the session
+// If the stream belongs to a watch with stats, the watch's stats are appended to the fields.
+func (s *Session) streamFields(ctx context.Context, fields ...interface{}) []interface{} {
+	if stats := util.WatchStatsFromContext(ctx); stats != nil {
+		fields = append(fields, stats.Fields()...)
+	}
+	return s.fields(fields...)
+}
@@ -0,0 +1,52 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.21
+// +build go1.21
+
+package logging
+
+import (
+	"github.com/atomix/go-client/pkg/client/session"
+	"log/slog"
+)
+
+// NewSlogLogger returns a session Logger that logs to the given log/slog Logger
+// Session fields are passed to slog as alternating key-value arguments.
+func NewSlogLogger(logger *slog.Logger) session.Logger {
+	return &slogLogger{
+		logger: logger,
+	}
+}
+
+// slogLogger is a log/slog implementation of session.Logger
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+func (l *slogLogger) Debug(msg string, fields ...interface{}) {
+	l.logger.Debug(msg, fields...)
+}
+
+func (l *slogLogger) Info(msg string, fields ...interface{}) {
+	l.logger.Info(msg, fields...)
+}
+
+func (l *slogLogger) Warn(msg string, fields ...interface{}) {
+	l.logger.Warn(msg, fields...)
+}
+
+func (l *slogLogger) Error(msg string, fields ...interface{}) {
+	l.logger.Error(msg, fields...)
+}
@@ -0,0 +1,161 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/atomix/go-client/pkg/client/session"
+	"github.com/prometheus/client_golang/prometheus"
+	"time"
+)
+
+const (
+	statusSuccess = "success"
+	statusFailure = "failure"
+)
+
+// primitiveLabel is the label for primitive names, which is only applied when enabled by WithPrimitiveLabel
+const primitiveLabel = "primitive_name"
+
+// Option is an option for Prometheus metrics
+type Option interface {
+	apply(options *options)
+}
+
+type options struct {
+	primitiveLabel bool
+}
+
+// WithPrimitiveLabel returns an Option to label metrics with the name of the primitive on which operations are
+// performed. Every primitive created by the application adds a new series to each metric, so the label should
+// only be enabled by applications that use a small, fixed set of primitives.
+func WithPrimitiveLabel() Option {
+	return primitiveLabelOption{}
+}
+
+type primitiveLabelOption struct{}
+
+func (o primitiveLabelOption) apply(options *options) {
+	options.primitiveLabel = true
+}
+
+// NewPrometheusMetrics returns session Metrics that are registered with the given Prometheus Registerer
+// atomix_client_operations_total counts completed operations and atomix_client_operation_duration_seconds
+// is a histogram of operation latencies. Both metrics are labeled by primitive type (e.g. "Value"), partition,
+// operation name (e.g. "value.Set"), operation type (e.g. "command") and status.
+// atomix_client_operation_retries_total counts resent requests and atomix_client_replica_queries_total counts
+// queries served by a replica rather than the leader.
+// Primitive names are not used as labels unless WithPrimitiveLabel is provided, to bound the number of series.
+func NewPrometheusMetrics(registerer prometheus.Registerer, opts ...Option) (session.Metrics, error) {
+	options := &options{}
+	for _, opt := range opts {
+		opt.apply(options)
+	}
+	opLabels := []string{"primitive_type", "partition", "operation", "operation_type"}
+	if options.primitiveLabel {
+		opLabels = append([]string{primitiveLabel}, opLabels...)
+	}
+	labels := append(append([]string{}, opLabels...), "status")
+
+	operations := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "atomix",
+		Subsystem: "client",
+		Name:      "operations_total",
+		Help:      "The total number of Atomix primitive operations.",
+	}, labels)
+	if err := registerer.Register(operations); err != nil {
+		return nil, err
+	}
+
+	durations := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "atomix",
+		Subsystem: "client",
+		Name:      "operation_duration_seconds",
+		Help:      "The latency of Atomix primitive operations in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, labels)
+	if err := registerer.Register(durations); err != nil {
+		registerer.Unregister(operations)
+		return nil, err
+	}
+
+	retries := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "atomix",
+		Subsystem: "client",
+		Name:      "operation_retries_total",
+		Help:      "The total number of retried Atomix primitive operation requests.",
+	}, opLabels)
+	if err := registerer.Register(retries); err != nil {
+		registerer.Unregister(operations)
+		registerer.Unregister(durations)
+		return nil, err
+	}
+
+	replicaQueries := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "atomix",
+		Subsystem: "client",
+		Name:      "replica_queries_total",
+		Help:      "The total number of Atomix primitive queries served by a replica.",
+	}, opLabels)
+	if err := registerer.Register(replicaQueries); err != nil {
+		registerer.Unregister(operations)
+		registerer.Unregister(durations)
+		registerer.Unregister(retries)
+		return nil, err
+	}
+
+	return &prometheusMetrics{
+		primitiveLabel: options.primitiveLabel,
+		operations:     operations,
+		durations:      durations,
+		retries:        retries,
+		replicaQueries: replicaQueries,
+	}, nil
+}
+
+// prometheusMetrics is a Prometheus implementation of session.Metrics
+type prometheusMetrics struct {
+	primitiveLabel bool
+	operations     *prometheus.CounterVec
+	durations      *prometheus.HistogramVec
+	retries        *prometheus.CounterVec
+	replicaQueries *prometheus.CounterVec
+}
+
+func (m *prometheusMetrics) Observe(op session.Operation, duration time.Duration, err error) {
+	status := statusSuccess
+	if err != nil {
+		status = statusFailure
+	}
+	labels := prometheus.Labels{
+		"primitive_type": string(op.PrimitiveType),
+		"partition":      string(op.Partition),
+		"operation":      op.Name,
+		"operation_type": string(op.Type),
+		"status":         status,
+	}
+	if m.primitiveLabel {
+		labels[primitiveLabel] = op.Primitive
+	}
+	m.operations.With(labels).Inc()
+	m.durations.With(labels).Observe(duration.Seconds())
+
+	delete(labels, "status")
+	if op.Retries > 0 {
+		m.retries.With(labels).Add(float64(op.Retries))
+	}
+	if op.Replica {
+		m.replicaQueries.With(labels).Inc()
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"errors"
+	"github.com/atomix/go-client/pkg/client/session"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// histogramCount returns the number of observations recorded by the duration histogram with the given labels
+func histogramCount(t *testing.T, registry *prometheus.Registry, labels map[string]string) uint64 {
+	families, err := registry.Gather()
+	assert.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != "atomix_client_operation_duration_seconds" {
+			continue
+		}
+	metrics:
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if labels[label.GetName()] != label.GetValue() {
+					continue metrics
+				}
+			}
+			return metric.GetHistogram().GetSampleCount()
+		}
+	}
+	return 0
+}
+
+func TestPrometheusMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m, err := NewPrometheusMetrics(registry)
+	assert.NoError(t, err)
+
+	_, err = NewPrometheusMetrics(registry)
+	assert.Error(t, err)
+
+	op := session.Operation{
+		Name:          "value.Set",
+		Primitive:     "foo",
+		PrimitiveType: "Value",
+		Type:          session.OperationCommand,
+		Partition:     "localhost:5000",
+		Retries:       2,
+	}
+	m.Observe(op, time.Millisecond, nil)
+	m.Observe(op, time.Millisecond, nil)
+	m.Observe(op, time.Second, errors.New("failed"))
+	op.Name, op.Type, op.Retries, op.Replica = "value.Get", session.OperationQuery, 0, true
+	m.Observe(op, time.Millisecond, nil)
+
+	metrics := m.(*prometheusMetrics)
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.operations.WithLabelValues("Value", "localhost:5000", "value.Set", "command", statusSuccess)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.operations.WithLabelValues("Value", "localhost:5000", "value.Set", "command", statusFailure)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.operations.WithLabelValues("Value", "localhost:5000", "value.Get", "query", statusSuccess)))
+	assert.Equal(t, 6.0, testutil.ToFloat64(metrics.retries.WithLabelValues("Value", "localhost:5000", "value.Set", "command")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.replicaQueries.WithLabelValues("Value", "localhost:5000", "value.Get", "query")))
+
+	labels := map[string]string{
+		"primitive_type": "Value",
+		"partition":      "localhost:5000",
+		"operation":      "value.Set",
+		"operation_type": "command",
+	}
+	labels["status"] = statusSuccess
+	assert.Equal(t, uint64(2), histogramCount(t, registry, labels))
+	labels["status"] = statusFailure
+	assert.Equal(t, uint64(1), histogramCount(t, registry, labels))
+
+	// Primitive names are not used as labels by default
+	families, err := registry.Gather()
+	assert.NoError(t, err)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				assert.NotEqual(t, primitiveLabel, label.GetName())
+			}
+		}
+	}
+}
+
+func TestPrometheusMetricsPrimitiveLabel(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m, err := NewPrometheusMetrics(registry, WithPrimitiveLabel())
+	assert.NoError(t, err)
+
+	op := session.Operation{
+		Name:          "value.Set",
+		Primitive:     "foo",
+		PrimitiveType: "Value",
+		Type:          session.OperationCommand,
+		Partition:     "localhost:5000",
+	}
+	m.Observe(op, time.Millisecond, nil)
+	m.Observe(op, time.Millisecond, errors.New("failed"))
+
+	metrics := m.(*prometheusMetrics)
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.operations.WithLabelValues("foo", "Value", "localhost:5000", "value.Set", "command", statusSuccess)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.operations.WithLabelValues("foo", "Value", "localhost:5000", "value.Set", "command", statusFailure)))
+	assert.Equal(t, uint64(1), histogramCount(t, registry, map[string]string{
+		"primitive_name": "foo",
+		"primitive_type": "Value",
+		"partition":      "localhost:5000",
+		"operation":      "value.Set",
+		"operation_type": "command",
+		"status":         statusFailure,
+	}))
+}
@@ -0,0 +1,101 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"github.com/atomix/go-client/pkg/client/primitive"
+	"github.com/atomix/go-client/pkg/client/util/net"
+	"time"
+)
+
+// OperationType is the type of a session operation
+type OperationType string
+
+const (
+	// OperationCommand is a session command
+	OperationCommand OperationType = "command"
+
+	// OperationQuery is a session query
+	OperationQuery OperationType = "query"
+
+	// OperationCommandStream is a session command stream
+	OperationCommandStream OperationType = "command-stream"
+
+	// OperationQueryStream is a session query stream
+	OperationQueryStream OperationType = "query-stream"
+)
+
+// Operation describes a single session operation
+type Operation struct {
+	// Name is the canonical name of the operation, e.g. "value.Set"
+	Name string
+
+	// Primitive is the name of the primitive on which the operation is performed
+	Primitive string
+
+	// PrimitiveType is the type of the primitive on which the operation is performed
+	PrimitiveType primitive.Type
+
+	// Type is the operation type
+	Type OperationType
+
+	// Partition is the address of the partition to which the operation is sent
+	Partition net.Address
+
+	// Replica indicates whether a query was served by a replica rather than the partition leader
+	Replica bool
+
+	// Retries is the number of times the operation's request was resent
+	Retries int
+}
+
+// operation tracks the span and metrics of an in-flight session operation
+type operation struct {
+	Operation
+	session *Session
+	span    Span
+	start   time.Time
+}
+
+// startOperation starts an operation with the given name and type, returning a context to propagate to requests
+func (s *Session) startOperation(ctx context.Context, name string, t OperationType) (context.Context, *operation) {
+	op := &operation{
+		Operation: Operation{
+			Name:          name,
+			Primitive:     s.Name.Name,
+			PrimitiveType: s.handler.Type(),
+			Type:          t,
+			Partition:     s.conns.Address,
+		},
+		session: s,
+		span:    noopSpan{},
+	}
+	if s.tracer != nil {
+		ctx, op.span = s.tracer.Start(ctx, op.Operation)
+	}
+	if s.metrics != nil {
+		op.start = time.Now()
+	}
+	return ctx, op
+}
+
+// end completes the operation, recording the span and metrics
+func (o *operation) end(err error) {
+	o.span.End(o.Operation, err)
+	if o.session.metrics != nil {
+		o.session.metrics.Observe(o.Operation, time.Since(o.start), err)
+	}
+}
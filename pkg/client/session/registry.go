@@ -0,0 +1,103 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrRegistryClosed is returned by New when the session's Registry has been closed
+var ErrRegistryClosed = errors.New("session registry is closed")
+
+// NewRegistry returns a new Registry
+func NewRegistry() *Registry {
+	return &Registry{
+		sessions: make(map[*Session]bool),
+	}
+}
+
+// Registry tracks the open sessions that share it so they can be closed together
+// Sessions are removed from the registry when they're closed or deleted, so the registry only references open
+// sessions.
+type Registry struct {
+	sessions map[*Session]bool
+	closed   bool
+	mu       sync.Mutex
+}
+
+// Sessions returns the number of open sessions in the registry
+func (r *Registry) Sessions() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.sessions)
+}
+
+// Close closes all open sessions in the registry
+// Once closed, new sessions cannot be added to the registry. All sessions are closed even if some fail to close,
+// and the first error is returned. Closing a closed registry is a no-op.
+func (r *Registry) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	sessions := make([]*Session, 0, len(r.sessions))
+	for session := range r.sessions {
+		sessions = append(sessions, session)
+	}
+	r.mu.Unlock()
+
+	var result error
+	for _, session := range sessions {
+		if err := session.Close(); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+// add adds an open session to the registry
+func (r *Registry) add(session *Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return ErrRegistryClosed
+	}
+	r.sessions[session] = true
+	return nil
+}
+
+// remove removes a closed session from the registry
+func (r *Registry) remove(session *Session) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sessions, session)
+}
+
+// WithRegistry returns a session Option to track the session in the given Registry
+// The registry may be shared by many sessions to close all the sessions opened by a client at once.
+func WithRegistry(registry *Registry) Option {
+	return registryOption{registry: registry}
+}
+
+type registryOption struct {
+	registry *Registry
+}
+
+func (o registryOption) prepare(options *options) {
+	options.registry = o.registry
+}
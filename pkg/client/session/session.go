@@ -20,10 +20,16 @@ import (
 	"github.com/atomix/api/proto/atomix/headers"
 	api "github.com/atomix/api/proto/atomix/primitive"
 	"github.com/atomix/go-client/pkg/client/primitive"
+	"github.com/atomix/go-client/pkg/client/util"
 	"github.com/atomix/go-client/pkg/client/util/net"
+	"github.com/cenkalti/backoff"
 	"github.com/google/uuid"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -46,6 +52,9 @@ func (o idOption) prepare(options *options) {
 }
 
 // WithTimeout returns a session Option to configure the session timeout
+// The timeout is sent to the server when the session is opened, and the session expires if no keep-alive is received
+// within it. Keep-alives are sent at half the timeout, so the timeout must be at least MinTimeout to leave time for a
+// keep-alive to complete. The default timeout is 30 seconds.
 func WithTimeout(timeout time.Duration) Option {
 	return timeoutOption{timeout: timeout}
 }
@@ -58,13 +67,219 @@ func (o timeoutOption) prepare(options *options) {
 	options.timeout = o.timeout
 }
 
-type options struct {
-	id      string
+// WithTracer returns a session Option to trace session operations with the given Tracer
+func WithTracer(tracer Tracer) Option {
+	return tracerOption{tracer: tracer}
+}
+
+type tracerOption struct {
+	tracer Tracer
+}
+
+func (o tracerOption) prepare(options *options) {
+	options.tracer = o.tracer
+}
+
+// WithMetrics returns a session Option to collect metrics for session operations
+func WithMetrics(metrics Metrics) Option {
+	return metricsOption{metrics: metrics}
+}
+
+type metricsOption struct {
+	metrics Metrics
+}
+
+func (o metricsOption) prepare(options *options) {
+	options.metrics = o.metrics
+}
+
+// WithReconnectLimit returns a session Option to limit consecutive failed attempts to re-establish a stream
+// Once the limit is reached, the stream is closed and ErrReconnectLimitExceeded is delivered to the channel
+// configured by WithStreamErrors. The limit must be at least 1; New returns ErrInvalidReconnectLimit otherwise.
+// By default the number of attempts is unlimited: like commands, streams are re-established with backoff until
+// their context is canceled.
+func WithReconnectLimit(limit int) Option {
+	return reconnectLimitOption{limit: limit}
+}
+
+type reconnectLimitOption struct {
+	limit int
+}
+
+func (o reconnectLimitOption) prepare(options *options) {
+	options.reconnectLimit = o.limit
+}
+
+// WithOperationTimeout returns a session Option to bound commands and queries that have no deadline
+// Each DoCommand and DoQuery call whose context has no deadline is given a derived context that times out after
+// the given duration. A deadline set by the caller always takes precedence, whether it's shorter or longer than
+// the operation timeout. By default operations are not bounded.
+func WithOperationTimeout(timeout time.Duration) Option {
+	return operationTimeoutOption{timeout: timeout}
+}
+
+type operationTimeoutOption struct {
 	timeout time.Duration
 }
 
+func (o operationTimeoutOption) prepare(options *options) {
+	options.operationTimeout = o.timeout
+}
+
+// WithReadRepair returns a session Option to repair stale reads served by replicas
+// When a query is served at an index older than the last index seen by the session, the session reconnects to the
+// leader and re-issues the query, returning the fresher result. Repairs are counted in the session's Stats and add
+// the latency of a second round trip to the repaired query. By default stale reads are returned as is.
+func WithReadRepair() Option {
+	return readRepairOption{}
+}
+
+type readRepairOption struct{}
+
+func (o readRepairOption) prepare(options *options) {
+	options.readRepair = true
+}
+
+// WithStreamErrors returns a session Option to deliver terminal stream errors to the given channel
+// An error is delivered before the stream is closed.
+func WithStreamErrors(ch chan<- error) Option {
+	return streamErrorsOption{ch: ch}
+}
+
+type streamErrorsOption struct {
+	ch chan<- error
+}
+
+func (o streamErrorsOption) prepare(options *options) {
+	options.streamErrors = o.ch
+}
+
+// WithSequenceWindow returns a session Option to limit the number of concurrent outstanding commands
+// Commands are sequenced by the session, and each outstanding command is tracked until it completes. By default
+// the window is unbounded. When the window is full, commands fail with ErrSequenceWindowFull unless the
+// WindowBlock policy is configured with WithSequenceWindowPolicy.
+func WithSequenceWindow(size int) Option {
+	return sequenceWindowOption{size: size}
+}
+
+type sequenceWindowOption struct {
+	size int
+}
+
+func (o sequenceWindowOption) prepare(options *options) {
+	options.windowSize = o.size
+}
+
+// WindowPolicy is the policy applied to commands when the sequence window is full
+type WindowPolicy int
+
+const (
+	// WindowReject fails commands with ErrSequenceWindowFull when the sequence window is full
+	WindowReject WindowPolicy = iota
+
+	// WindowBlock blocks commands until a slot in the sequence window is freed or the context is canceled
+	WindowBlock
+)
+
+// WithSequenceWindowPolicy returns a session Option to configure the policy applied when the sequence window is full
+func WithSequenceWindowPolicy(policy WindowPolicy) Option {
+	return sequenceWindowPolicyOption{policy: policy}
+}
+
+type sequenceWindowPolicyOption struct {
+	policy WindowPolicy
+}
+
+func (o sequenceWindowPolicyOption) prepare(options *options) {
+	options.windowPolicy = o.policy
+}
+
+// WithPartitioner returns a session Option to configure how keys are mapped to partitions
+// By default keys are mapped with util.GetPartitionIndex. util.GetRendezvousPartitionIndex remaps fewer keys when
+// the number of partitions changes, but places keys differently, so all clients of a primitive must use the
+// same partitioner and existing data must be migrated when changing it.
+func WithPartitioner(partitioner util.Partitioner) Option {
+	return partitionerOption{partitioner: partitioner}
+}
+
+type partitionerOption struct {
+	partitioner util.Partitioner
+}
+
+func (o partitionerOption) prepare(options *options) {
+	options.partitioner = o.partitioner
+}
+
+// GetPartitioner returns the Partitioner configured by the given options
+func GetPartitioner(opts ...Option) util.Partitioner {
+	options := &options{
+		partitioner: util.GetPartitionIndex,
+	}
+	for i := range opts {
+		opts[i].prepare(options)
+	}
+	return options.partitioner
+}
+
+// WithFaultInjector returns a session Option to inject faults into session operations
+// This option is intended for chaos testing only. By default no faults are injected.
+func WithFaultInjector(injector Injector) Option {
+	return faultInjectorOption{injector: injector}
+}
+
+type faultInjectorOption struct {
+	injector Injector
+}
+
+func (o faultInjectorOption) prepare(options *options) {
+	options.injector = o.injector
+}
+
+type options struct {
+	id               string
+	timeout          time.Duration
+	tracer           Tracer
+	metrics          Metrics
+	reconnectLimit   int
+	streamErrors     chan<- error
+	windowSize       int
+	windowPolicy     WindowPolicy
+	partitioner      util.Partitioner
+	injector         Injector
+	watches          *WatchLimiter
+	operationTimeout time.Duration
+	readRepair       bool
+	logger           Logger
+	registry         *Registry
+	replicas         map[net.Address][]net.Address
+}
+
+// ErrSequenceWindowFull is returned by commands when the session's sequence window is full
+var ErrSequenceWindowFull = errors.New("sequence window is full")
+
+// MinTimeout is the minimum session timeout
+const MinTimeout = time.Second
+
+// ErrInvalidTimeout is returned by New when the session timeout is less than MinTimeout
+var ErrInvalidTimeout = errors.New("session timeout must be at least 1s")
+
+// unlimitedReconnects is the default reconnect limit, which is never reached
+const unlimitedReconnects = math.MaxInt32
+
+// ErrInvalidReconnectLimit is returned by New when the stream reconnect limit is less than 1
+var ErrInvalidReconnectLimit = errors.New("stream reconnect limit must be at least 1")
+
+// ErrReconnectLimitExceeded is delivered to stream error channels when a stream could not be re-established
+var ErrReconnectLimitExceeded = errors.New("stream reconnect limit exceeded")
+
+// errStreamFailed is recorded for streams closed by an error response
+var errStreamFailed = errors.New("stream failed with an error response")
+
 // Handler provides session management for a primitive implementation
 type Handler interface {
+	// Type returns the type of the primitive whose session is handled
+	Type() primitive.Type
+
 	// Create is called to create the session
 	Create(ctx context.Context, session *Session) error
 
@@ -83,12 +298,20 @@ type Handler interface {
 // handler is the primitive's session handler
 func New(ctx context.Context, name primitive.Name, address net.Address, handler Handler, opts ...Option) (*Session, error) {
 	options := &options{
-		id:      uuid.New().String(),
-		timeout: 30 * time.Second,
+		id:             uuid.New().String(),
+		timeout:        30 * time.Second,
+		reconnectLimit: unlimitedReconnects,
+		logger:         noopLogger{},
 	}
 	for i := range opts {
 		opts[i].prepare(options)
 	}
+	if options.timeout < MinTimeout {
+		return nil, ErrInvalidTimeout
+	}
+	if options.reconnectLimit < 1 {
+		return nil, ErrInvalidReconnectLimit
+	}
 	session := &Session{
 		ID: options.id,
 		Name: &api.Name{
@@ -97,10 +320,29 @@ func New(ctx context.Context, name primitive.Name, address net.Address, handler
 		},
 		conns:   net.NewConns(address),
 		handler: handler,
+		tracer:  options.tracer,
+		metrics: options.metrics,
+		logger:  options.logger,
+		stats:   &Stats{},
 		Timeout: options.timeout,
 		streams: make(map[uint64]*Stream),
 		mu:      sync.RWMutex{},
 		ticker:  time.NewTicker(options.timeout / 2),
+
+		reconnectLimit:   options.reconnectLimit,
+		streamErrors:     options.streamErrors,
+		windowPolicy:     options.windowPolicy,
+		injector:         options.injector,
+		watches:          options.watches,
+		operationTimeout: options.operationTimeout,
+		readRepair:       options.readRepair,
+		registry:         options.registry,
+	}
+	if options.windowSize > 0 {
+		session.window = make(chan struct{}, options.windowSize)
+	}
+	for _, replica := range options.replicas[address] {
+		session.replicas = append(session.replicas, net.NewConns(replica))
 	}
 	if err := session.start(ctx); err != nil {
 		return nil, err
@@ -114,43 +356,111 @@ type Session struct {
 	Name       *api.Name
 	Timeout    time.Duration
 	SessionID  uint64
+	state      State
 	conns      *net.Conns
 	handler    Handler
+	tracer     Tracer
+	metrics    Metrics
+	logger     Logger
+	stats      *Stats
 	lastIndex  uint64
 	requestID  uint64
 	responseID uint64
 	streams    map[uint64]*Stream
 	mu         sync.RWMutex
 	ticker     *time.Ticker
+
+	reconnectLimit   int
+	streamErrors     chan<- error
+	window           chan struct{}
+	windowPolicy     WindowPolicy
+	injector         Injector
+	watches          *WatchLimiter
+	operationTimeout time.Duration
+	readRepair       bool
+	registry         *Registry
+	replicas         []*net.Conns
+	leaderKnown      int32
+	shutdownOnce     sync.Once
+	shutdownErr      error
 }
 
 // start creates the session and begins keep-alives
 func (s *Session) start(ctx context.Context) error {
 	err := s.handler.Create(ctx, s)
 	if err != nil {
+		s.logger.Error("Failed to create session", s.fields("error", err)...)
 		return err
 	}
+	if s.registry != nil {
+		if err := s.registry.add(s); err != nil {
+			s.handler.Close(ctx, s)
+			s.release()
+			return err
+		}
+	}
+	s.recordKeepAlive(nil)
+	s.logger.Info("Created session", s.fields("sessionID", s.SessionID, "partition", s.conns.Address)...)
 
 	go func() {
 		for range s.ticker.C {
-			_ = s.handler.KeepAlive(context.TODO(), s)
+			s.keepAlive()
 		}
 	}()
 	return nil
 }
 
 // Close closes the session
+// Close and Delete may be called any number of times from any goroutine. Only the first call closes the session,
+// and subsequent calls return its result.
 func (s *Session) Close() error {
-	err := s.handler.Close(context.TODO(), s)
-	s.ticker.Stop()
-	return err
+	return s.shutdown(func() error {
+		err := s.handler.Close(context.TODO(), s)
+		if err != nil {
+			s.logger.Error("Failed to close session", s.fields("error", err)...)
+		} else {
+			s.logger.Info("Closed session", s.fields()...)
+		}
+		return err
+	})
 }
 
 // Delete closes the session and deletes the primitive
+// If the primitive does not exist, primitive.ErrNotFound is returned.
+// If the session has already been closed, the primitive is not deleted and the result of the close is returned.
 func (s *Session) Delete() error {
-	err := s.handler.Delete(context.TODO(), s)
+	return s.shutdown(func() error {
+		err := s.handler.Delete(context.TODO(), s)
+		if err != nil {
+			s.logger.Error("Failed to delete primitive", s.fields("error", err)...)
+		} else {
+			s.logger.Info("Deleted primitive", s.fields()...)
+		}
+		return err
+	})
+}
+
+// shutdown closes the session with the given function and releases it the first time it's called
+// The result of the first call is returned by all subsequent calls.
+func (s *Session) shutdown(f func() error) error {
+	s.shutdownOnce.Do(func() {
+		s.shutdownErr = f()
+		s.release()
+	})
+	return s.shutdownErr
+}
+
+// release stops keep-alives and releases the session's connection once the session has been closed
+func (s *Session) release() {
 	s.ticker.Stop()
-	return err
+	s.setState(func(State) State { return StateClosed })
+	s.conns.Close()
+	for _, replica := range s.replicas {
+		replica.Close()
+	}
+	if s.registry != nil {
+		s.registry.remove(s)
+	}
 }
 
 // getState gets the header for the current state of the session
@@ -222,69 +532,232 @@ func (s *Session) DoKeepAlive(ctx context.Context, f func(ctx context.Context, c
 }
 
 // DoClose sends a session close request
+// Close requests are used to close the session and to delete the primitive. If the primitive does not exist,
+// primitive.ErrNotFound is returned.
 func (s *Session) DoClose(ctx context.Context, f func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error)) error {
-	return s.doSession(ctx, f)
+	err := s.doSession(ctx, f)
+	if status.Code(err) == codes.NotFound {
+		return primitive.ErrNotFound
+	}
+	return err
 }
 
 func (s *Session) doSession(ctx context.Context, f func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error)) error {
 	header := s.getState()
-	_, err := s.doRequest(header, func(conn *grpc.ClientConn) (*headers.ResponseHeader, interface{}, error) {
+	_, _, err := s.doRequest(ctx, nil, header, func(conn *grpc.ClientConn) (*headers.ResponseHeader, interface{}, error) {
 		return f(ctx, conn, header)
 	})
 	return err
 }
 
 // DoQuery sends a session query request
-func (s *Session) DoQuery(ctx context.Context, f func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error)) (interface{}, error) {
+// The name is the canonical name of the operation, e.g. "value.Get", used to identify the query in traces and metrics.
+func (s *Session) DoQuery(ctx context.Context, name string, f func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error)) (interface{}, error) {
+	response, _, err := s.doQuery(ctx, name, f, false)
+	return response, err
+}
+
+// doQuery sends a session query request, falling back to the session's replicas if requested
+// Returns a bool indicating whether the response was served by a replica after the leader was unavailable.
+func (s *Session) doQuery(ctx context.Context, name string, f func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error), fallback bool) (interface{}, bool, error) {
+	ctx, cancel := s.operationContext(ctx)
+	defer cancel()
+	ctx, op := s.startOperation(ctx, name, OperationQuery)
+	drop, err := s.injectFault(ctx, op)
+	if err != nil {
+		op.end(err)
+		return nil, false, err
+	}
+	atomic.AddUint64(&s.stats.Queries, 1)
+	header := s.getQueryHeader()
+	request := func(conn *grpc.ClientConn) (*headers.ResponseHeader, interface{}, error) {
+		return f(ctx, conn, header)
+	}
+	stale := false
+	if fallback && len(s.replicas) > 0 {
+		request = func(conn *grpc.ClientConn) (*headers.ResponseHeader, interface{}, error) {
+			responseHeader, response, err := f(ctx, conn, header)
+			if err != nil && status.Code(err) == codes.Unavailable {
+				if responseHeader, response, ok := s.fallbackQuery(ctx, header, f); ok {
+					stale = true
+					return responseHeader, response, nil
+				}
+			}
+			return responseHeader, response, err
+		}
+	}
+	responseHeader, response, err := s.doRequest(ctx, op, header, request)
+	if err == nil {
+		if stale {
+			op.Replica = true
+		} else {
+			op.Replica = s.recordQuery(responseHeader)
+			if s.readRepair && responseHeader.Index < header.Index {
+				response, err = s.repairRead(ctx, op, responseHeader, f)
+			}
+		}
+		if err == nil && drop {
+			response, err = nil, ErrResponseDropped
+		}
+	}
+	op.end(err)
+	return response, stale, err
+}
+
+// repairRead re-issues a stale query to the leader, returning the fresher result
+// A query is stale if it was served at an index older than the last index seen by the session. Repairing a read
+// adds the latency of a second round trip to the leader to the query.
+func (s *Session) repairRead(
+	ctx context.Context,
+	op *operation,
+	staleHeader *headers.ResponseHeader,
+	f func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error)) (interface{}, error) {
+	atomic.AddUint64(&s.stats.ReadRepairs, 1)
+	s.reconnect(staleHeader.Leader)
 	header := s.getQueryHeader()
-	return s.doRequest(header, func(conn *grpc.ClientConn) (*headers.ResponseHeader, interface{}, error) {
+	_, response, err := s.doRequest(ctx, op, header, func(conn *grpc.ClientConn) (*headers.ResponseHeader, interface{}, error) {
 		return f(ctx, conn, header)
 	})
+	op.Replica = false
+	return response, err
 }
 
 // DoCommand sends a session command request
-func (s *Session) DoCommand(ctx context.Context, f func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error)) (interface{}, error) {
+// The name is the canonical name of the operation, e.g. "value.Set", used to identify the command in traces and metrics.
+func (s *Session) DoCommand(ctx context.Context, name string, f func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error)) (interface{}, error) {
+	ctx, cancel := s.operationContext(ctx)
+	defer cancel()
+	ctx, op := s.startOperation(ctx, name, OperationCommand)
+	if err := s.acquireWindow(ctx); err != nil {
+		op.end(err)
+		return nil, err
+	}
+	defer s.releaseWindow()
+	drop, err := s.injectFault(ctx, op)
+	if err != nil {
+		op.end(err)
+		return nil, err
+	}
+	atomic.AddUint64(&s.stats.Commands, 1)
 	stream, header := s.nextStreamHeader()
 	defer stream.Close()
-	return s.doRequest(header, func(conn *grpc.ClientConn) (*headers.ResponseHeader, interface{}, error) {
+	_, response, err := s.doRequest(ctx, op, header, func(conn *grpc.ClientConn) (*headers.ResponseHeader, interface{}, error) {
 		return f(ctx, conn, header)
 	})
+	if err == nil && drop {
+		response, err = nil, ErrResponseDropped
+	}
+	op.end(err)
+	return response, err
 }
 
-func (s *Session) doRequest(requestHeader *headers.RequestHeader, f func(conn *grpc.ClientConn) (*headers.ResponseHeader, interface{}, error)) (interface{}, error) {
-	for {
+// operationContext returns a context bounded by the operation timeout if the given context has no deadline
+func (s *Session) operationContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.operationTimeout == 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, s.operationTimeout)
+}
+
+// acquireWindow acquires a slot in the sequence window for a command
+func (s *Session) acquireWindow(ctx context.Context) error {
+	if s.window == nil {
+		return nil
+	}
+	if s.windowPolicy == WindowBlock {
+		select {
+		case s.window <- struct{}{}:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	select {
+	case s.window <- struct{}{}:
+		return nil
+	default:
+		return ErrSequenceWindowFull
+	}
+}
+
+// releaseWindow releases a command's slot in the sequence window
+func (s *Session) releaseWindow() {
+	if s.window != nil {
+		<-s.window
+	}
+}
+
+// doRequest sends a request, retrying until it succeeds or fails with an error
+// The operation may be nil for session management requests. If the context ends while retrying a partition
+// that is unavailable, the last Unavailable error is returned rather than the context's error.
+func (s *Session) doRequest(ctx context.Context, op *operation, requestHeader *headers.RequestHeader, f func(conn *grpc.ClientConn) (*headers.ResponseHeader, interface{}, error)) (*headers.ResponseHeader, interface{}, error) {
+	var unavailable error
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			atomic.AddUint64(&s.stats.Retries, 1)
+			if op != nil {
+				op.Retries++
+			}
+		}
 		conn, err := s.conns.Connect()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		if responseHeader, response, err := f(conn); err == nil {
 			switch responseHeader.Status {
 			case headers.ResponseStatus_OK:
 				s.RecordResponse(requestHeader, responseHeader)
-				return response, err
+				return responseHeader, response, err
 			case headers.ResponseStatus_NOT_LEADER:
-				s.conns.Reconnect(net.Address(responseHeader.Leader))
+				s.reconnect(responseHeader.Leader)
 				continue
 			case headers.ResponseStatus_ERROR:
-				return nil, errors.New("an unknown error occurred")
+				return nil, nil, errors.New("an unknown error occurred")
 			}
+		} else if ctx.Err() != nil {
+			// Stop retrying once the operation's context is canceled or its deadline is exceeded
+			if status.Code(err) == codes.Unavailable {
+				return nil, nil, err
+			} else if unavailable != nil {
+				return nil, nil, unavailable
+			}
+			return nil, nil, ctx.Err()
+		} else if status.Code(err) == codes.NotFound {
+			// The primitive does not exist, so retrying cannot succeed
+			return nil, nil, err
+		} else {
+			if status.Code(err) == codes.Unavailable {
+				unavailable = err
+			} else {
+				unavailable = nil
+			}
+			s.logger.Debug("Retrying request", s.fields("attempt", attempt+1, "error", err)...)
 		}
 	}
 }
 
 // DoQueryStream sends a session query stream request
+// The name is the canonical name of the operation used to identify the stream in traces and metrics.
 func (s *Session) DoQueryStream(
 	ctx context.Context,
+	name string,
 	f func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error),
 	responseFunc func(interface{}) (*headers.ResponseHeader, interface{}, error)) (<-chan interface{}, error) {
+	ctx, op := s.startOperation(ctx, name, OperationQueryStream)
+	atomic.AddUint64(&s.stats.Queries, 1)
 	conn, err := s.conns.Connect()
 	if err != nil {
+		op.end(err)
 		return nil, err
 	}
 
 	requestHeader := s.getQueryHeader()
 	responses, err := f(ctx, conn, requestHeader)
 	if err != nil {
+		op.end(err)
 		return nil, err
 	}
 
@@ -294,9 +767,12 @@ func (s *Session) DoQueryStream(
 
 	select {
 	case <-handshakeCh:
+		op.end(nil)
 		return responseCh, nil
 	case <-time.After(15 * time.Second):
-		return nil, errors.New("handshake timed out")
+		err := errors.New("handshake timed out")
+		op.end(err)
+		return nil, err
 	}
 }
 
@@ -308,16 +784,27 @@ func (s *Session) queryStream(
 	requestHeader *headers.RequestHeader,
 	handshakeCh chan<- struct{},
 	responseCh chan interface{}) {
+	// Failed attempts to re-establish the stream are backed off and counted against the reconnect limit until a
+	// response is received
+	b := newStreamBackOff(ctx)
+	failures := 0
 	for {
 		responseHeader, response, err := responseFunc(responses)
 		if err != nil {
+			if ctx.Err() == nil {
+				util.StreamErrorFromContext(ctx).Record(err)
+			}
 			close(responseCh)
 			return
 		}
 
 		switch responseHeader.Type {
 		case headers.ResponseType_OPEN_STREAM:
-			close(handshakeCh)
+			if handshakeCh != nil {
+				s.recordQuery(responseHeader)
+				close(handshakeCh)
+				handshakeCh = nil
+			}
 		case headers.ResponseType_CLOSE_STREAM:
 			close(responseCh)
 			return
@@ -326,22 +813,21 @@ func (s *Session) queryStream(
 			case headers.ResponseStatus_OK:
 				// Record the response
 				s.RecordResponse(requestHeader, responseHeader)
+				failures = 0
+				b.Reset()
 				responseCh <- response
 			case headers.ResponseStatus_NOT_LEADER:
-				s.conns.Reconnect(net.Address(responseHeader.Leader))
-				conn, err := s.conns.Connect()
+				s.reconnect(responseHeader.Leader)
+				responses, err = s.reconnectStream(ctx, f, requestHeader, b, &failures)
 				if err != nil {
-					close(responseCh)
-				} else {
-					responses, err := f(ctx, conn, requestHeader)
-					if err != nil {
-						close(responseCh)
-					} else {
-						go s.queryStream(ctx, f, responseFunc, responses, requestHeader, nil, responseCh)
+					if ctx.Err() == nil {
+						s.streamError(ctx, err)
 					}
+					close(responseCh)
+					return
 				}
-				return
 			case headers.ResponseStatus_ERROR:
+				util.StreamErrorFromContext(ctx).Record(errStreamFailed)
 				close(responseCh)
 				return
 			}
@@ -350,12 +836,22 @@ func (s *Session) queryStream(
 }
 
 // DoCommandStream sends a session command stream request
+// The name is the canonical name of the operation used to identify the stream in traces and metrics.
 func (s *Session) DoCommandStream(
 	ctx context.Context,
+	name string,
 	f func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error),
 	responseFunc func(interface{}) (*headers.ResponseHeader, interface{}, error)) (<-chan interface{}, error) {
+	ctx, op := s.startOperation(ctx, name, OperationCommandStream)
+	if err := s.openWatch(ctx); err != nil {
+		op.end(err)
+		return nil, err
+	}
+	atomic.AddUint64(&s.stats.Commands, 1)
 	conn, err := s.conns.Connect()
 	if err != nil {
+		s.closeWatch()
+		op.end(err)
 		return nil, err
 	}
 
@@ -363,6 +859,8 @@ func (s *Session) DoCommandStream(
 	responses, err := f(ctx, conn, requestHeader)
 	if err != nil {
 		stream.Close()
+		s.closeWatch()
+		op.end(err)
 		return nil, err
 	}
 
@@ -375,13 +873,19 @@ func (s *Session) DoCommandStream(
 
 	handshakeCh := make(chan struct{})
 	responseCh := make(chan interface{})
-	go s.commandStream(ctx, f, responseFunc, responses, stream, requestHeader, handshakeCh, responseCh)
+	go func() {
+		defer s.closeWatch()
+		s.commandStream(ctx, f, responseFunc, responses, stream, requestHeader, handshakeCh, responseCh)
+	}()
 
 	select {
 	case <-handshakeCh:
+		op.end(nil)
 		return responseCh, nil
 	case <-time.After(15 * time.Second):
-		return nil, errors.New("handshake timed out")
+		err := errors.New("handshake timed out")
+		op.end(err)
+		return nil, err
 	}
 }
 
@@ -394,18 +898,41 @@ func (s *Session) commandStream(
 	requestHeader *headers.RequestHeader,
 	handshakeCh chan<- struct{},
 	responseCh chan<- interface{}) {
+	// Consecutive stream failures and failed attempts to re-establish the stream are backed off and counted
+	// against the reconnect limit until a response is received
+	b := newStreamBackOff(ctx)
+	failures := 0
 	for {
 		responseHeader, response, err := responseFunc(responses)
 		if err != nil {
-			close(responseCh)
-			stream.Close()
-			return
+			if ctx.Err() != nil || !isRetryableStreamError(err) {
+				if ctx.Err() == nil {
+					util.StreamErrorFromContext(ctx).Record(err)
+				}
+				close(responseCh)
+				stream.Close()
+				return
+			}
+
+			// Re-establish the stream from the last serialized response. Replayed responses are skipped by the stream.
+			s.logger.Warn("Stream failed, re-establishing", s.streamFields(ctx, "stream", stream.ID, "error", err)...)
+			responses, err = s.reconnectStream(ctx, f, requestHeader, b, &failures)
+			if err != nil {
+				if ctx.Err() == nil {
+					s.streamError(ctx, err)
+				}
+				close(responseCh)
+				stream.Close()
+				return
+			}
+			continue
 		}
 
 		switch responseHeader.Type {
 		case headers.ResponseType_OPEN_STREAM:
 			if stream.Serialize(responseHeader) && handshakeCh != nil {
 				close(handshakeCh)
+				handshakeCh = nil
 			}
 		case headers.ResponseType_CLOSE_STREAM:
 			if stream.Serialize(responseHeader) {
@@ -418,28 +945,26 @@ func (s *Session) commandStream(
 			case headers.ResponseStatus_OK:
 				// Record the response
 				s.RecordResponse(requestHeader, responseHeader)
+				failures = 0
+				b.Reset()
 
 				// Attempt to serialize the response to the stream and skip the response if serialization failed.
 				if stream.Serialize(responseHeader) {
 					responseCh <- response
 				}
 			case headers.ResponseStatus_NOT_LEADER:
-				s.conns.Reconnect(net.Address(responseHeader.Leader))
-				conn, err := s.conns.Connect()
+				s.reconnect(responseHeader.Leader)
+				responses, err = s.reconnectStream(ctx, f, requestHeader, b, &failures)
 				if err != nil {
+					if ctx.Err() == nil {
+						s.streamError(ctx, err)
+					}
 					close(responseCh)
 					stream.Close()
-				} else {
-					responses, err := f(ctx, conn, requestHeader)
-					if err != nil {
-						close(responseCh)
-						stream.Close()
-					} else {
-						go s.commandStream(ctx, f, responseFunc, responses, stream, requestHeader, nil, responseCh)
-					}
+					return
 				}
-				return
 			case headers.ResponseStatus_ERROR:
+				util.StreamErrorFromContext(ctx).Record(errStreamFailed)
 				close(responseCh)
 				stream.Close()
 				return
@@ -448,6 +973,74 @@ func (s *Session) commandStream(
 	}
 }
 
+// newStreamBackOff returns the backoff for attempts to re-establish a stream
+// Attempts are backed off exponentially for as long as the stream's context is not canceled.
+func newStreamBackOff(ctx context.Context) backoff.BackOffContext {
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = 0
+	return backoff.WithContext(b, ctx)
+}
+
+// isRetryableStreamError returns whether the given stream error is transient and the stream can be re-established
+func isRetryableStreamError(err error) bool {
+	return status.Code(err) == codes.Unavailable
+}
+
+// reconnectStream re-establishes a stream, counting each attempt against the session's reconnect limit
+// failures is the number of consecutive failures since the stream last delivered a response, and b is the stream's
+// backoff. Every attempt after the first consecutive failure is backed off. If the stream cannot be re-established
+// within the reconnect limit, ErrReconnectLimitExceeded is returned.
+func (s *Session) reconnectStream(
+	ctx context.Context,
+	f func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error),
+	requestHeader *headers.RequestHeader,
+	b backoff.BackOff,
+	failures *int) (interface{}, error) {
+	for {
+		*failures++
+		if *failures > s.reconnectLimit {
+			return nil, ErrReconnectLimitExceeded
+		}
+		if *failures > 1 {
+			select {
+			case <-time.After(b.NextBackOff()):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		if responses, err := s.reestablishStream(ctx, f, requestHeader); err == nil {
+			return responses, nil
+		}
+	}
+}
+
+// reestablishStream makes a single attempt to re-establish a stream
+func (s *Session) reestablishStream(
+	ctx context.Context,
+	f func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error),
+	requestHeader *headers.RequestHeader) (interface{}, error) {
+	atomic.AddUint64(&s.stats.Retries, 1)
+	conn, err := s.conns.Connect()
+	if err != nil {
+		return nil, err
+	}
+	return f(ctx, conn, requestHeader)
+}
+
+// streamError delivers a terminal stream error to the stream errors channel if configured
+// The error is also recorded to the StreamError carried by the stream's context, if any.
+func (s *Session) streamError(ctx context.Context, err error) {
+	s.logger.Error("Stream closed", s.streamFields(ctx, "error", err)...)
+	util.StreamErrorFromContext(ctx).Record(err)
+	if s.streamErrors == nil {
+		return
+	}
+	select {
+	case s.streamErrors <- err:
+	case <-ctx.Done():
+	}
+}
+
 // RecordResponse records the index in a response header
 func (s *Session) RecordResponse(requestHeader *headers.RequestHeader, responseHeader *headers.ResponseHeader) {
 	// Use a double-checked lock to avoid locking when multiple responses are received for an index.
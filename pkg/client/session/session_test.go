@@ -16,9 +16,16 @@ package session
 
 import (
 	"context"
+	"errors"
 	"github.com/atomix/api/proto/atomix/headers"
 	"github.com/atomix/go-client/pkg/client/primitive"
+	"github.com/atomix/go-client/pkg/client/util"
+	"github.com/atomix/go-client/pkg/client/util/net"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sync"
 	"testing"
 	"time"
 )
@@ -27,6 +34,38 @@ func TestSessionOptions(t *testing.T) {
 	options := &options{}
 	WithTimeout(5 * time.Second).prepare(options)
 	assert.Equal(t, 5*time.Second, options.timeout)
+	WithReconnectLimit(3).prepare(options)
+	assert.Equal(t, 3, options.reconnectLimit)
+	errCh := make(chan error)
+	WithStreamErrors(errCh).prepare(options)
+	assert.NotNil(t, options.streamErrors)
+	WithSequenceWindow(10).prepare(options)
+	assert.Equal(t, 10, options.windowSize)
+	assert.Equal(t, WindowReject, options.windowPolicy)
+	WithSequenceWindowPolicy(WindowBlock).prepare(options)
+	assert.Equal(t, WindowBlock, options.windowPolicy)
+	WithPartitioner(util.GetRendezvousPartitionIndex).prepare(options)
+	assert.NotNil(t, options.partitioner)
+	WithOperationTimeout(time.Second).prepare(options)
+	assert.Equal(t, time.Second, options.operationTimeout)
+	WithReadRepair().prepare(options)
+	assert.True(t, options.readRepair)
+}
+
+func TestGetPartitioner(t *testing.T) {
+	i, err := GetPartitioner()("foo", 3)
+	assert.NoError(t, err)
+	j, err := util.GetPartitionIndex("foo", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, j, i)
+
+	for n := 1; n < 10; n++ {
+		i, err = GetPartitioner(WithPartitioner(util.GetRendezvousPartitionIndex))("foo", n)
+		assert.NoError(t, err)
+		j, err = util.GetRendezvousPartitionIndex("foo", n)
+		assert.NoError(t, err)
+		assert.Equal(t, j, i)
+	}
 }
 
 func newTestHandler() *testHandler {
@@ -45,6 +84,10 @@ type testHandler struct {
 	delete    chan bool
 }
 
+func (h *testHandler) Type() primitive.Type {
+	return "Test"
+}
+
 func (h *testHandler) Create(ctx context.Context, session *Session) error {
 	h.create <- true
 	return nil
@@ -156,3 +199,1027 @@ func TestSession(t *testing.T) {
 
 	assert.True(t, <-handler.keepAlive)
 }
+
+type testTracer struct {
+	ops   []Operation
+	ended []Operation
+	errs  []error
+}
+
+func (t *testTracer) Start(ctx context.Context, op Operation) (context.Context, Span) {
+	t.ops = append(t.ops, op)
+	return ctx, &testSpan{tracer: t}
+}
+
+type testSpan struct {
+	tracer *testTracer
+}
+
+func (s *testSpan) End(op Operation, err error) {
+	s.tracer.ended = append(s.tracer.ended, op)
+	s.tracer.errs = append(s.tracer.errs, err)
+}
+
+func TestSessionTracer(t *testing.T) {
+	name := primitive.NewName("a", "b", "c", "d")
+	handler := newTestHandler()
+	tracer := &testTracer{}
+	session, err := New(context.TODO(), name, "localhost:5000", handler, WithTracer(tracer))
+	assert.NoError(t, err)
+	assert.True(t, <-handler.create)
+
+	response, err := session.DoCommand(context.TODO(), "test.Command", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		return &headers.ResponseHeader{Status: headers.ResponseStatus_OK}, "foo", nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", response)
+
+	_, err = session.DoQuery(context.TODO(), "test.Query", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		return &headers.ResponseHeader{Status: headers.ResponseStatus_ERROR}, nil, nil
+	})
+	assert.Error(t, err)
+
+	assert.Len(t, tracer.ops, 2)
+	assert.Equal(t, "test.Command", tracer.ops[0].Name)
+	assert.Equal(t, OperationCommand, tracer.ops[0].Type)
+	assert.Equal(t, "d", tracer.ops[0].Primitive)
+	assert.Equal(t, "localhost:5000", string(tracer.ops[0].Partition))
+	assert.Equal(t, "test.Query", tracer.ops[1].Name)
+	assert.Equal(t, OperationQuery, tracer.ops[1].Type)
+	assert.Len(t, tracer.errs, 2)
+	assert.NoError(t, tracer.errs[0])
+	assert.Error(t, tracer.errs[1])
+
+	// Replica reads and retries are only known when the span ends
+	redirected := false
+	_, err = session.DoQuery(context.TODO(), "test.Query", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		if !redirected {
+			redirected = true
+			return &headers.ResponseHeader{Status: headers.ResponseStatus_NOT_LEADER, Leader: "localhost:5001"}, nil, nil
+		}
+		return &headers.ResponseHeader{Status: headers.ResponseStatus_OK, Leader: "localhost:5002"}, nil, nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, tracer.ops, 3)
+	assert.False(t, tracer.ops[2].Replica)
+	assert.Equal(t, 0, tracer.ops[2].Retries)
+	assert.Len(t, tracer.ended, 3)
+	assert.Equal(t, "test.Query", tracer.ended[2].Name)
+	assert.Equal(t, primitive.Type("Test"), tracer.ended[2].PrimitiveType)
+	assert.True(t, tracer.ended[2].Replica)
+	assert.Equal(t, 1, tracer.ended[2].Retries)
+}
+
+type testMetrics struct {
+	ops  []Operation
+	errs []error
+}
+
+func (m *testMetrics) Observe(op Operation, duration time.Duration, err error) {
+	m.ops = append(m.ops, op)
+	m.errs = append(m.errs, err)
+}
+
+func TestSessionMetrics(t *testing.T) {
+	name := primitive.NewName("a", "b", "c", "d")
+	handler := newTestHandler()
+	metrics := &testMetrics{}
+	session, err := New(context.TODO(), name, "localhost:5000", handler, WithMetrics(metrics))
+	assert.NoError(t, err)
+	assert.True(t, <-handler.create)
+
+	_, err = session.DoCommand(context.TODO(), "test.Command", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		return &headers.ResponseHeader{Status: headers.ResponseStatus_OK}, nil, nil
+	})
+	assert.NoError(t, err)
+
+	_, err = session.DoQuery(context.TODO(), "test.Query", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		return &headers.ResponseHeader{Status: headers.ResponseStatus_ERROR}, nil, nil
+	})
+	assert.Error(t, err)
+
+	assert.Len(t, metrics.ops, 2)
+	assert.Equal(t, "test.Command", metrics.ops[0].Name)
+	assert.Equal(t, OperationCommand, metrics.ops[0].Type)
+	assert.Equal(t, "d", metrics.ops[0].Primitive)
+	assert.NoError(t, metrics.errs[0])
+	assert.Equal(t, "test.Query", metrics.ops[1].Name)
+	assert.Equal(t, OperationQuery, metrics.ops[1].Type)
+	assert.Error(t, metrics.errs[1])
+}
+
+func TestSessionStats(t *testing.T) {
+	name := primitive.NewName("a", "b", "c", "d")
+	handler := newTestHandler()
+	metrics := &testMetrics{}
+	session, err := New(context.TODO(), name, "localhost:5000", handler, WithMetrics(metrics))
+	assert.NoError(t, err)
+	assert.True(t, <-handler.create)
+
+	for i := 0; i < 2; i++ {
+		_, err = session.DoCommand(context.TODO(), "test.Command", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+			return &headers.ResponseHeader{Status: headers.ResponseStatus_OK}, nil, nil
+		})
+		assert.NoError(t, err)
+	}
+
+	_, err = session.DoQuery(context.TODO(), "test.Query", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		return &headers.ResponseHeader{Status: headers.ResponseStatus_OK, Leader: "localhost:5000"}, nil, nil
+	})
+	assert.NoError(t, err)
+
+	_, err = session.DoQuery(context.TODO(), "test.Query", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		return &headers.ResponseHeader{Status: headers.ResponseStatus_OK, Leader: "localhost:5001"}, nil, nil
+	})
+	assert.NoError(t, err)
+
+	redirected := false
+	_, err = session.DoQuery(context.TODO(), "test.Query", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		if !redirected {
+			redirected = true
+			return &headers.ResponseHeader{Status: headers.ResponseStatus_NOT_LEADER, Leader: "localhost:5002"}, nil, nil
+		}
+		return &headers.ResponseHeader{Status: headers.ResponseStatus_OK, Leader: "localhost:5002"}, nil, nil
+	})
+	assert.NoError(t, err)
+
+	stats := session.Stats()
+	assert.Equal(t, uint64(2), stats.Commands)
+	assert.Equal(t, uint64(3), stats.Queries)
+	assert.Equal(t, uint64(2), stats.LeaderQueries)
+	assert.Equal(t, uint64(1), stats.ReplicaQueries)
+	assert.Equal(t, uint64(1), stats.Retries)
+	assert.Equal(t, uint64(1), stats.Reconnects)
+
+	assert.Len(t, metrics.ops, 5)
+	assert.False(t, metrics.ops[2].Replica)
+	assert.True(t, metrics.ops[3].Replica)
+	assert.Equal(t, 0, metrics.ops[3].Retries)
+	assert.False(t, metrics.ops[4].Replica)
+	assert.Equal(t, 1, metrics.ops[4].Retries)
+}
+
+func TestSessionStatsLeaderUnknown(t *testing.T) {
+	name := primitive.NewName("a", "b", "c", "d")
+	handler := newTestHandler()
+	session, err := New(context.TODO(), name, "localhost:5000", handler, WithReadRepair())
+	assert.NoError(t, err)
+	assert.True(t, <-handler.create)
+
+	// The partition address is served by the leader, which names itself in the first response
+	calls := 0
+	_, err = session.DoQuery(context.TODO(), "test.Query", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		calls++
+		return &headers.ResponseHeader{Status: headers.ResponseStatus_OK, Leader: "localhost:5001", Index: header.Index}, nil, nil
+	})
+	assert.NoError(t, err)
+
+	stats := session.Stats()
+	assert.Equal(t, uint64(1), stats.LeaderQueries)
+	assert.Equal(t, uint64(0), stats.ReplicaQueries)
+	assert.Equal(t, uint64(0), stats.ReadRepairs)
+	assert.Equal(t, 1, calls)
+
+	// Once the session is redirected to the leader, queries served by other nodes are attributed to replicas
+	session.reconnect("localhost:5001")
+	_, err = session.DoQuery(context.TODO(), "test.Query", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		return &headers.ResponseHeader{Status: headers.ResponseStatus_OK, Leader: "localhost:5002", Index: header.Index}, nil, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), session.Stats().ReplicaQueries)
+}
+
+func TestSessionTimeout(t *testing.T) {
+	name := primitive.NewName("a", "b", "c", "d")
+	handler := newTestHandler()
+	for _, timeout := range []time.Duration{-time.Second, 0, MinTimeout / 2} {
+		_, err := New(context.TODO(), name, "localhost:5000", handler, WithTimeout(timeout))
+		assert.Equal(t, ErrInvalidTimeout, err)
+	}
+	assert.Len(t, handler.create, 0)
+
+	session, err := New(context.TODO(), name, "localhost:5000", handler, WithTimeout(MinTimeout))
+	assert.NoError(t, err)
+	assert.True(t, <-handler.create)
+	assert.Equal(t, MinTimeout, session.Timeout)
+}
+
+func TestSessionReconnectLimitInvalid(t *testing.T) {
+	name := primitive.NewName("a", "b", "c", "d")
+	handler := newTestHandler()
+	for _, limit := range []int{-1, 0} {
+		_, err := New(context.TODO(), name, "localhost:5000", handler, WithReconnectLimit(limit))
+		assert.Equal(t, ErrInvalidReconnectLimit, err)
+	}
+	assert.Len(t, handler.create, 0)
+
+	_, err := New(context.TODO(), name, "localhost:5000", handler, WithReconnectLimit(1))
+	assert.NoError(t, err)
+	assert.True(t, <-handler.create)
+}
+
+func TestSessionRegistry(t *testing.T) {
+	name := primitive.NewName("a", "b", "c", "d")
+	registry := NewRegistry()
+	handler1 := newTestHandler()
+	session1, err := New(context.TODO(), name, "localhost:5000", handler1, WithRegistry(registry))
+	assert.NoError(t, err)
+	assert.True(t, <-handler1.create)
+	handler2 := newTestHandler()
+	_, err = New(context.TODO(), name, "localhost:5000", handler2, WithRegistry(registry))
+	assert.NoError(t, err)
+	assert.True(t, <-handler2.create)
+	assert.Equal(t, 2, registry.Sessions())
+
+	assert.NoError(t, session1.Close())
+	assert.True(t, <-handler1.close)
+	assert.Equal(t, 1, registry.Sessions())
+
+	assert.NoError(t, registry.Close())
+	assert.True(t, <-handler2.close)
+	assert.Equal(t, 0, registry.Sessions())
+	assert.NoError(t, registry.Close())
+
+	handler3 := newTestHandler()
+	_, err = New(context.TODO(), name, "localhost:5000", handler3, WithRegistry(registry))
+	assert.Equal(t, ErrRegistryClosed, err)
+	assert.True(t, <-handler3.create)
+	assert.True(t, <-handler3.close)
+	assert.Equal(t, 0, registry.Sessions())
+}
+
+func TestSessionCloseConcurrent(t *testing.T) {
+	name := primitive.NewName("a", "b", "c", "d")
+	handler := newTestHandler()
+	session, err := New(context.TODO(), name, "localhost:5000", handler)
+	assert.NoError(t, err)
+	assert.True(t, <-handler.create)
+
+	// The handler's channels are buffered for a single call, so closing the session more than once blocks
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, session.Close())
+		}()
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, session.Delete())
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, len(handler.close)+len(handler.delete))
+	assert.Equal(t, StateClosed, session.State())
+
+	// Errors are returned by all subsequent calls
+	handler = newTestHandler()
+	session, err = New(context.TODO(), name, "localhost:5000", &failingHandler{testHandler: handler})
+	assert.NoError(t, err)
+	assert.True(t, <-handler.create)
+	assert.EqualError(t, session.Close(), "close failed")
+	assert.EqualError(t, session.Close(), "close failed")
+	assert.EqualError(t, session.Delete(), "close failed")
+}
+
+// failingHandler is a testHandler that fails to close sessions
+type failingHandler struct {
+	*testHandler
+}
+
+func (h *failingHandler) Close(ctx context.Context, session *Session) error {
+	return errors.New("close failed")
+}
+
+func TestSessionDeleteNotFound(t *testing.T) {
+	name := primitive.NewName("a", "b", "c", "d")
+	handler := newTestHandler()
+	session, err := New(context.TODO(), name, "localhost:5000", &notFoundHandler{testHandler: handler})
+	assert.NoError(t, err)
+	assert.True(t, <-handler.create)
+
+	assert.Equal(t, primitive.ErrNotFound, session.Delete())
+	assert.Equal(t, StateClosed, session.State())
+}
+
+func TestSessionCommandNotFound(t *testing.T) {
+	name := primitive.NewName("a", "b", "c", "d")
+	handler := newTestHandler()
+	session, err := New(context.TODO(), name, "localhost:5000", handler)
+	assert.NoError(t, err)
+	assert.True(t, <-handler.create)
+
+	// NotFound errors from operations other than close and delete are not retried and are returned as is
+	attempts := 0
+	_, err = session.DoCommand(context.TODO(), "test.Command", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		attempts++
+		return nil, nil, status.Error(codes.NotFound, "key not found")
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.NotEqual(t, primitive.ErrNotFound, err)
+	assert.Equal(t, 1, attempts)
+}
+
+// notFoundHandler is a testHandler whose primitive does not exist when it's deleted
+type notFoundHandler struct {
+	*testHandler
+}
+
+func (h *notFoundHandler) Delete(ctx context.Context, session *Session) error {
+	return session.DoClose(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		return nil, nil, status.Error(codes.NotFound, "not found")
+	})
+}
+
+func TestSessionReconnectLimit(t *testing.T) {
+	name := primitive.NewName("a", "b", "c", "d")
+
+	// By default streams are re-established until their context is canceled
+	handler := newTestHandler()
+	session, err := New(context.TODO(), name, "localhost:5000", handler)
+	assert.NoError(t, err)
+	assert.True(t, <-handler.create)
+	assert.Equal(t, unlimitedReconnects, session.reconnectLimit)
+
+	// Command and query streams that are redirected to a new leader count attempts against the same limit
+	for _, kind := range []string{"command", "query"} {
+		handler := newTestHandler()
+		errCh := make(chan error, 1)
+		session, err := New(context.TODO(), name, "localhost:5000", handler, WithReconnectLimit(2), WithStreamErrors(errCh))
+		assert.NoError(t, err)
+		assert.True(t, <-handler.create)
+
+		responses := make(chan *headers.ResponseHeader, 2)
+		responses <- &headers.ResponseHeader{Type: headers.ResponseType_OPEN_STREAM, ResponseID: 1}
+		responses <- &headers.ResponseHeader{Type: headers.ResponseType_RESPONSE, Status: headers.ResponseStatus_NOT_LEADER, Leader: "localhost:5001"}
+
+		attempts := 0
+		f := func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
+			attempts++
+			if attempts > 1 {
+				return nil, errors.New("not found")
+			}
+			return responses, nil
+		}
+		responseFunc := func(responses interface{}) (*headers.ResponseHeader, interface{}, error) {
+			return <-responses.(chan *headers.ResponseHeader), nil, nil
+		}
+		var ch <-chan interface{}
+		if kind == "command" {
+			ch, err = session.DoCommandStream(context.TODO(), "test.Watch", f, responseFunc)
+		} else {
+			ch, err = session.DoQueryStream(context.TODO(), "test.Elements", f, responseFunc)
+		}
+		assert.NoError(t, err)
+
+		_, ok := <-ch
+		assert.False(t, ok, kind)
+		assert.Equal(t, ErrReconnectLimitExceeded, <-errCh, kind)
+		assert.Equal(t, 3, attempts, kind)
+		assert.Equal(t, uint64(1), session.Stats().Reconnects, kind)
+		assert.Equal(t, uint64(2), session.Stats().Retries, kind)
+	}
+}
+
+func TestSessionReconnectLimitStreamFailure(t *testing.T) {
+	name := primitive.NewName("a", "b", "c", "d")
+	handler := newTestHandler()
+	errCh := make(chan error, 1)
+	session, err := New(context.TODO(), name, "localhost:5000", handler, WithReconnectLimit(3), WithStreamErrors(errCh))
+	assert.NoError(t, err)
+	assert.True(t, <-handler.create)
+
+	// Every re-established stream fails again before delivering a response
+	attempts := 0
+	streamErr := &util.StreamError{}
+	ch, err := session.DoCommandStream(util.NewStreamErrorContext(context.TODO(), streamErr), "test.Watch", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
+		attempts++
+		stream := make(chan streamResult, 2)
+		if attempts == 1 {
+			stream <- streamResult{header: &headers.ResponseHeader{Type: headers.ResponseType_OPEN_STREAM, ResponseID: 1}}
+		}
+		stream <- streamResult{err: status.Error(codes.Unavailable, "connection reset")}
+		return stream, nil
+	}, func(responses interface{}) (*headers.ResponseHeader, interface{}, error) {
+		result := <-responses.(chan streamResult)
+		if result.err != nil {
+			return nil, nil, result.err
+		}
+		return result.header, result.header.ResponseID, nil
+	})
+	assert.NoError(t, err)
+
+	_, ok := <-ch
+	assert.False(t, ok)
+	assert.Equal(t, ErrReconnectLimitExceeded, <-errCh)
+	assert.Equal(t, ErrReconnectLimitExceeded, streamErr.Err())
+	assert.Equal(t, 4, attempts)
+	assert.Equal(t, uint64(3), session.Stats().Retries)
+}
+
+// testLogger is a Logger that records logged messages
+type testLogger struct {
+	mu       sync.Mutex
+	messages []string
+	fields   [][]interface{}
+}
+
+func (l *testLogger) log(msg string, fields []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, msg)
+	l.fields = append(l.fields, fields)
+}
+
+func (l *testLogger) Debug(msg string, fields ...interface{}) {
+	l.log(msg, fields)
+}
+
+func (l *testLogger) Info(msg string, fields ...interface{}) {
+	l.log(msg, fields)
+}
+
+func (l *testLogger) Warn(msg string, fields ...interface{}) {
+	l.log(msg, fields)
+}
+
+func (l *testLogger) Error(msg string, fields ...interface{}) {
+	l.log(msg, fields)
+}
+
+func TestSessionLogger(t *testing.T) {
+	name := primitive.NewName("a", "b", "c", "d")
+	handler := newTestHandler()
+	logger := &testLogger{}
+	session, err := New(context.TODO(), name, "localhost:5000", handler, WithID("test"), WithLogger(logger))
+	assert.NoError(t, err)
+	assert.True(t, <-handler.create)
+
+	session.reconnect("localhost:5001")
+
+	// Stream failures are logged with the stats of the stream's watch
+	stats := &util.WatchStats{}
+	stats.RecordDelivered()
+	stats.RecordVersion(3)
+	err = errors.New("failed")
+	session.streamError(util.NewWatchStatsContext(context.TODO(), stats), err)
+
+	assert.NoError(t, session.Close())
+	assert.True(t, <-handler.close)
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	assert.Equal(t, []string{"Created session", "Reconnected session", "Stream closed", "Closed session"}, logger.messages)
+	assert.Equal(t, []interface{}{"session", "test", "primitive", "c.d", "leader", "localhost:5001"}, logger.fields[1])
+	assert.Equal(t, []interface{}{"session", "test", "primitive", "c.d", "error", err, "delivered", uint64(1), "dropped", uint64(0), "lastVersion", uint64(3)}, logger.fields[2][:12])
+	assert.Equal(t, "sinceLastEvent", logger.fields[2][12])
+}
+
+func TestSessionNilLogger(t *testing.T) {
+	name := primitive.NewName("a", "b", "c", "d")
+	handler := newTestHandler()
+	session, err := New(context.TODO(), name, "localhost:5000", handler, WithLogger(nil))
+	assert.NoError(t, err)
+	assert.True(t, <-handler.create)
+	assert.Equal(t, noopLogger{}, session.logger)
+
+	session.reconnect("localhost:5001")
+	session.streamError(context.TODO(), errors.New("failed"))
+
+	assert.NoError(t, session.Close())
+	assert.True(t, <-handler.close)
+}
+
+// streamResult is a response or error received from a test stream
+type streamResult struct {
+	header *headers.ResponseHeader
+	err    error
+}
+
+func TestSessionStreamReconnect(t *testing.T) {
+	name := primitive.NewName("a", "b", "c", "d")
+	handler := newTestHandler()
+	session, err := New(context.TODO(), name, "localhost:5000", handler)
+	assert.NoError(t, err)
+	assert.True(t, <-handler.create)
+
+	// The first stream delivers an event and is then killed by a transient failure
+	first := make(chan streamResult, 3)
+	first <- streamResult{header: &headers.ResponseHeader{Type: headers.ResponseType_OPEN_STREAM, ResponseID: 1}}
+	first <- streamResult{header: &headers.ResponseHeader{Type: headers.ResponseType_RESPONSE, Status: headers.ResponseStatus_OK, ResponseID: 2, Index: 2}}
+	first <- streamResult{err: status.Error(codes.Unavailable, "connection reset")}
+
+	// The restored stream replays from the start and then fails with a non-retryable error
+	second := make(chan streamResult, 5)
+	second <- streamResult{header: &headers.ResponseHeader{Type: headers.ResponseType_OPEN_STREAM, ResponseID: 1}}
+	second <- streamResult{header: &headers.ResponseHeader{Type: headers.ResponseType_RESPONSE, Status: headers.ResponseStatus_OK, ResponseID: 2, Index: 2}}
+	second <- streamResult{header: &headers.ResponseHeader{Type: headers.ResponseType_RESPONSE, Status: headers.ResponseStatus_OK, ResponseID: 3, Index: 3}}
+	second <- streamResult{err: status.Error(codes.Internal, "internal error")}
+
+	streams := []chan streamResult{first, second}
+	attempts := 0
+	ch, err := session.DoCommandStream(context.TODO(), "test.Watch", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
+		stream := streams[attempts]
+		attempts++
+		return stream, nil
+	}, func(responses interface{}) (*headers.ResponseHeader, interface{}, error) {
+		result := <-responses.(chan streamResult)
+		if result.err != nil {
+			return nil, nil, result.err
+		}
+		return result.header, result.header.ResponseID, nil
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, uint64(2), <-ch)
+	assert.Equal(t, uint64(3), <-ch)
+	_, ok := <-ch
+	assert.False(t, ok)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, uint64(1), session.Stats().Retries)
+}
+
+func TestSessionSequenceWindow(t *testing.T) {
+	name := primitive.NewName("a", "b", "c", "d")
+	handler := newTestHandler()
+	session, err := New(context.TODO(), name, "localhost:5000", handler, WithSequenceWindow(2))
+	assert.NoError(t, err)
+	assert.True(t, <-handler.create)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	done := make(chan error)
+	command := func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		started <- struct{}{}
+		<-release
+		return &headers.ResponseHeader{Status: headers.ResponseStatus_OK}, nil, nil
+	}
+
+	// Saturate the window
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := session.DoCommand(context.TODO(), "test.Command", command)
+			done <- err
+		}()
+		<-started
+	}
+
+	_, err = session.DoCommand(context.TODO(), "test.Command", command)
+	assert.Equal(t, ErrSequenceWindowFull, err)
+
+	close(release)
+	assert.NoError(t, <-done)
+	assert.NoError(t, <-done)
+
+	_, err = session.DoCommand(context.TODO(), "test.Command", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		return &headers.ResponseHeader{Status: headers.ResponseStatus_OK}, nil, nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestSessionSequenceWindowBlock(t *testing.T) {
+	name := primitive.NewName("a", "b", "c", "d")
+	handler := newTestHandler()
+	session, err := New(context.TODO(), name, "localhost:5000", handler, WithSequenceWindow(1), WithSequenceWindowPolicy(WindowBlock))
+	assert.NoError(t, err)
+	assert.True(t, <-handler.create)
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	command := func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		started <- struct{}{}
+		<-release
+		return &headers.ResponseHeader{Status: headers.ResponseStatus_OK}, nil, nil
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := session.DoCommand(context.TODO(), "test.Command", command)
+		done <- err
+	}()
+	<-started
+
+	// A blocked command fails when its context is canceled
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = session.DoCommand(ctx, "test.Command", command)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	// A blocked command proceeds once a slot is freed
+	go func() {
+		_, err := session.DoCommand(context.TODO(), "test.Command", command)
+		done <- err
+	}()
+	select {
+	case <-started:
+		t.Error("command was sent while the window was full")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	assert.NoError(t, <-done)
+	<-started
+	assert.NoError(t, <-done)
+}
+
+func TestSessionFaultInjector(t *testing.T) {
+	name := primitive.NewName("a", "b", "c", "d")
+	handler := newTestHandler()
+	injector := &RandomInjector{}
+	session, err := New(context.TODO(), name, "localhost:5000", handler, WithFaultInjector(injector))
+	assert.NoError(t, err)
+	assert.True(t, <-handler.create)
+
+	sent := 0
+	command := func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		sent++
+		return &headers.ResponseHeader{Status: headers.ResponseStatus_OK}, "foo", nil
+	}
+
+	// No faults are injected by default
+	response, err := session.DoCommand(context.TODO(), "test.Command", command)
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", response)
+	assert.Equal(t, 1, sent)
+
+	// Injected errors are returned without sending the request
+	injector.ErrorProbability = 1
+	injector.Error = errors.New("injected")
+	_, err = session.DoCommand(context.TODO(), "test.Command", command)
+	assert.EqualError(t, err, "injected")
+	_, err = session.DoQuery(context.TODO(), "test.Query", command)
+	assert.EqualError(t, err, "injected")
+	assert.Equal(t, 1, sent)
+
+	// Dropped responses are sent but fail with ErrResponseDropped
+	injector.ErrorProbability = 0
+	injector.DropProbability = 1
+	response, err = session.DoCommand(context.TODO(), "test.Command", command)
+	assert.Equal(t, ErrResponseDropped, err)
+	assert.Nil(t, response)
+	_, err = session.DoQuery(context.TODO(), "test.Query", command)
+	assert.Equal(t, ErrResponseDropped, err)
+	assert.Equal(t, 3, sent)
+
+	// Latency is added before the request is sent
+	injector.DropProbability = 0
+	injector.LatencyProbability = 1
+	injector.Latency = 100 * time.Millisecond
+	start := time.Now()
+	_, err = session.DoCommand(context.TODO(), "test.Command", command)
+	assert.NoError(t, err)
+	assert.True(t, time.Since(start) >= injector.Latency)
+	assert.Equal(t, 4, sent)
+
+	// Latency is bounded by the operation's context
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	injector.Latency = time.Minute
+	_, err = session.DoQuery(ctx, "test.Query", command)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, 4, sent)
+}
+
+// openTestWatch opens a command stream that remains open until the returned channel is closed
+func openTestWatch(ctx context.Context, session *Session) (chan<- struct{}, error) {
+	closeCh := make(chan struct{})
+	opened := false
+	_, err := session.DoCommandStream(ctx, "test.Watch", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
+		return closeCh, nil
+	}, func(responses interface{}) (*headers.ResponseHeader, interface{}, error) {
+		if !opened {
+			opened = true
+			return &headers.ResponseHeader{Type: headers.ResponseType_OPEN_STREAM, ResponseID: 1}, nil, nil
+		}
+		<-responses.(chan struct{})
+		return nil, nil, errors.New("closed")
+	})
+	return closeCh, err
+}
+
+func TestSessionWatchLimiter(t *testing.T) {
+	name := primitive.NewName("a", "b", "c", "d")
+	limiter := NewWatchLimiter(2, WatchReject)
+
+	handler1 := newTestHandler()
+	session1, err := New(context.TODO(), name, "localhost:5000", handler1, WithWatchLimiter(limiter))
+	assert.NoError(t, err)
+	assert.True(t, <-handler1.create)
+
+	handler2 := newTestHandler()
+	session2, err := New(context.TODO(), name, "localhost:5000", handler2, WithWatchLimiter(limiter))
+	assert.NoError(t, err)
+	assert.True(t, <-handler2.create)
+
+	close1, err := openTestWatch(context.TODO(), session1)
+	assert.NoError(t, err)
+	_, err = openTestWatch(context.TODO(), session2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, limiter.Streams())
+	assert.Equal(t, int64(1), session1.Stats().WatchStreams)
+	assert.Equal(t, int64(1), session2.Stats().WatchStreams)
+
+	// The limit is shared by both sessions
+	_, err = openTestWatch(context.TODO(), session1)
+	assert.Equal(t, ErrTooManyWatches, err)
+	assert.Equal(t, 2, limiter.Streams())
+
+	// Closing a stream frees a slot
+	close(close1)
+	for i := 0; i < 100 && limiter.Streams() > 1; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 1, limiter.Streams())
+	assert.Equal(t, int64(0), session1.Stats().WatchStreams)
+
+	_, err = openTestWatch(context.TODO(), session1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, limiter.Streams())
+}
+
+func TestSessionWatchLimiterBlock(t *testing.T) {
+	name := primitive.NewName("a", "b", "c", "d")
+	handler := newTestHandler()
+	limiter := NewWatchLimiter(1, WatchBlock)
+	session, err := New(context.TODO(), name, "localhost:5000", handler, WithWatchLimiter(limiter))
+	assert.NoError(t, err)
+	assert.True(t, <-handler.create)
+
+	closeCh, err := openTestWatch(context.TODO(), session)
+	assert.NoError(t, err)
+
+	// A blocked stream fails when its context is canceled
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = openTestWatch(ctx, session)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, 1, limiter.Streams())
+
+	// A blocked stream is opened once the open stream is closed
+	done := make(chan error)
+	go func() {
+		_, err := openTestWatch(context.TODO(), session)
+		done <- err
+	}()
+	select {
+	case <-done:
+		t.Error("stream was opened while the limit was reached")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(closeCh)
+	assert.NoError(t, <-done)
+	assert.Equal(t, 1, limiter.Streams())
+}
+
+func TestSessionOperationTimeout(t *testing.T) {
+	name := primitive.NewName("a", "b", "c", "d")
+	handler := newTestHandler()
+	session, err := New(context.TODO(), name, "localhost:5000", handler, WithOperationTimeout(100*time.Millisecond))
+	assert.NoError(t, err)
+	assert.True(t, <-handler.create)
+
+	block := func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		<-ctx.Done()
+		return nil, nil, ctx.Err()
+	}
+
+	// Operations without a deadline are bounded by the operation timeout
+	start := time.Now()
+	_, err = session.DoCommand(context.TODO(), "test.Command", block)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.True(t, time.Since(start) >= 100*time.Millisecond)
+
+	_, err = session.DoQuery(context.TODO(), "test.Query", block)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	// Deadlines set by the caller take precedence over the operation timeout
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	expected, _ := ctx.Deadline()
+	_, err = session.DoQuery(ctx, "test.Query", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		deadline, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.Equal(t, expected, deadline)
+		return &headers.ResponseHeader{Status: headers.ResponseStatus_OK}, nil, nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestSessionUnavailable(t *testing.T) {
+	name := primitive.NewName("a", "b", "c", "d")
+
+	// Requests to a stopped partition fail with Unavailable until the context ends
+	stopped := func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		return nil, nil, status.Error(codes.Unavailable, "connection refused")
+	}
+
+	sessions := make([]*Session, 2)
+	for i := range sessions {
+		handler := newTestHandler()
+		session, err := New(context.TODO(), name, "localhost:5000", handler)
+		assert.NoError(t, err)
+		assert.True(t, <-handler.create)
+		sessions[i] = session
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := sessions[0].DoCommand(ctx, "test.Command", stopped)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	// A partitioned operation over stopped partitions reports that all partitions are unavailable
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = util.ExecutePartitionsAsync(len(sessions), func(i int) (interface{}, error) {
+		return sessions[i].DoQuery(ctx, "test.Query", stopped)
+	})
+	assert.Equal(t, util.ErrAllPartitionsUnavailable, err)
+
+	// Requests that were not retrying an unavailable partition still fail with the context's error
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = sessions[1].DoCommand(ctx, "test.Command", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		<-ctx.Done()
+		return nil, nil, ctx.Err()
+	})
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+// pingHandler is a session Handler whose keep-alives fail with err
+type pingHandler struct {
+	testHandler
+	err error
+}
+
+func (h *pingHandler) Create(ctx context.Context, session *Session) error {
+	session.RecordResponse(&headers.RequestHeader{}, &headers.ResponseHeader{SessionID: 10, Index: 10})
+	return nil
+}
+
+func (h *pingHandler) KeepAlive(ctx context.Context, session *Session) error {
+	time.Sleep(10 * time.Millisecond)
+	return h.err
+}
+
+func (h *pingHandler) Close(ctx context.Context, session *Session) error {
+	return nil
+}
+
+func TestSessionHealth(t *testing.T) {
+	name := primitive.NewName("a", "b", "c", "d")
+	handler := &pingHandler{}
+	session, err := New(context.TODO(), name, "localhost:5000", handler, WithTimeout(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), session.SessionID)
+	assert.Equal(t, net.Address("localhost:5000"), session.Partition())
+	assert.Equal(t, StateOpen, session.State())
+	assert.True(t, session.Healthy())
+
+	latency, err := session.Ping(context.TODO())
+	assert.NoError(t, err)
+	assert.True(t, latency >= 10*time.Millisecond)
+	assert.True(t, session.Healthy())
+
+	handler.err = errors.New("unavailable")
+	_, err = session.Ping(context.TODO())
+	assert.EqualError(t, err, "unavailable")
+	assert.Equal(t, StateSuspended, session.State())
+	assert.False(t, session.Healthy())
+
+	handler.err = nil
+	_, err = session.Ping(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, StateOpen, session.State())
+
+	err = session.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, StateClosed, session.State())
+
+	_, err = session.Ping(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, StateClosed, session.State())
+}
+
+func TestSessionReadRepair(t *testing.T) {
+	name := primitive.NewName("a", "b", "c", "d")
+	for _, readRepair := range []bool{false, true} {
+		handler := newTestHandler()
+		var opts []Option
+		if readRepair {
+			opts = append(opts, WithReadRepair())
+		}
+		session, err := New(context.TODO(), name, "localhost:5000", handler, opts...)
+		assert.NoError(t, err)
+		assert.True(t, <-handler.create)
+
+		_, err = session.DoCommand(context.TODO(), "test.Command", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+			return &headers.ResponseHeader{Status: headers.ResponseStatus_OK, Index: 10}, nil, nil
+		})
+		assert.NoError(t, err)
+
+		// The first query is served by a lagging replica
+		queries := 0
+		response, err := session.DoQuery(context.TODO(), "test.Query", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+			queries++
+			assert.Equal(t, uint64(10), header.Index)
+			if queries == 1 {
+				return &headers.ResponseHeader{Status: headers.ResponseStatus_OK, Index: 5, Leader: "localhost:5001"}, "stale", nil
+			}
+			return &headers.ResponseHeader{Status: headers.ResponseStatus_OK, Index: 10, Leader: "localhost:5001"}, "fresh", nil
+		})
+		assert.NoError(t, err)
+
+		stats := session.Stats()
+		if readRepair {
+			assert.Equal(t, "fresh", response)
+			assert.Equal(t, 2, queries)
+			assert.Equal(t, uint64(1), stats.ReadRepairs)
+			assert.Equal(t, uint64(1), stats.Reconnects)
+		} else {
+			assert.Equal(t, "stale", response)
+			assert.Equal(t, 1, queries)
+			assert.Equal(t, uint64(0), stats.ReadRepairs)
+		}
+
+		// Reads that are up to date are not repaired
+		_, err = session.DoQuery(context.TODO(), "test.Query", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+			return &headers.ResponseHeader{Status: headers.ResponseStatus_OK, Index: 10}, nil, nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, stats.ReadRepairs, session.Stats().ReadRepairs)
+	}
+}
+
+func TestSessionQueryFallback(t *testing.T) {
+	name := primitive.NewName("a", "b", "c", "d")
+	handler := newTestHandler()
+	replicas := map[net.Address][]net.Address{
+		"localhost:5000": {"localhost:5001", "localhost:5002"},
+	}
+	session, err := New(context.TODO(), name, "localhost:5000", handler, WithReplicas(replicas))
+	assert.NoError(t, err)
+	assert.True(t, <-handler.create)
+
+	_, err = session.DoCommand(context.TODO(), "test.Command", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		return &headers.ResponseHeader{Status: headers.ResponseStatus_OK, Index: 10}, nil, nil
+	})
+	assert.NoError(t, err)
+
+	// The leader is unavailable and the first replica fails, so the query is served by the second replica
+	// Queries sent to replicas are identified by the relaxed header, which omits the session's last index.
+	var indexes []uint64
+	response, stale, err := session.DoQueryWithFallback(context.TODO(), "test.Query", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		indexes = append(indexes, header.Index)
+		switch len(indexes) {
+		case 1:
+			return nil, nil, status.Error(codes.Unavailable, "unavailable")
+		case 2:
+			return nil, nil, errors.New("failed")
+		default:
+			return &headers.ResponseHeader{Status: headers.ResponseStatus_OK, Index: 5}, "stale", nil
+		}
+	})
+	assert.NoError(t, err)
+	assert.True(t, stale)
+	assert.Equal(t, "stale", response)
+	assert.Equal(t, []uint64{10, 0, 0}, indexes)
+	assert.Equal(t, uint64(1), session.Stats().FallbackQueries)
+
+	// Queries that don't request a fallback are never sent to the replicas
+	indexes = nil
+	ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+	_, err = session.DoQuery(ctx, "test.Query", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		if header.Index != 10 && len(indexes) == 0 {
+			indexes = append(indexes, header.Index)
+		}
+		return nil, nil, status.Error(codes.Unavailable, "unavailable")
+	})
+	cancel()
+	assert.Error(t, err)
+	assert.Empty(t, indexes)
+
+	// Errors other than an unavailable leader are retried against the leader
+	indexes = nil
+	response, stale, err = session.DoQueryWithFallback(context.TODO(), "test.Query", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		indexes = append(indexes, header.Index)
+		if len(indexes) == 1 {
+			return nil, nil, errors.New("failed")
+		}
+		return &headers.ResponseHeader{Status: headers.ResponseStatus_OK, Index: 10}, "fresh", nil
+	})
+	assert.NoError(t, err)
+	assert.False(t, stale)
+	assert.Equal(t, "fresh", response)
+	assert.Equal(t, []uint64{10, 10}, indexes)
+
+	// Queries served by the leader are not stale
+	response, stale, err = session.DoQueryWithFallback(context.TODO(), "test.Query", func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+		return &headers.ResponseHeader{Status: headers.ResponseStatus_OK, Index: 10}, "fresh", nil
+	})
+	assert.NoError(t, err)
+	assert.False(t, stale)
+	assert.Equal(t, "fresh", response)
+	assert.Equal(t, uint64(1), session.Stats().FallbackQueries)
+	assert.NoError(t, session.Close())
+}
@@ -0,0 +1,96 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"github.com/atomix/api/proto/atomix/headers"
+	"github.com/atomix/go-client/pkg/client/util/net"
+	"sync/atomic"
+)
+
+// Stats is a snapshot of the operation counts of a session
+type Stats struct {
+	// Commands is the total number of commands and command streams sent by the session
+	Commands uint64
+
+	// Queries is the total number of queries and query streams sent by the session
+	Queries uint64
+
+	// LeaderQueries is the number of queries served by the partition leader
+	LeaderQueries uint64
+
+	// ReplicaQueries is the number of queries served by a replica other than the partition leader
+	ReplicaQueries uint64
+
+	// Retries is the number of requests resent after a failure or a redirect to the leader
+	Retries uint64
+
+	// Reconnects is the number of times the session reconnected to a new leader
+	Reconnects uint64
+
+	// ReadRepairs is the number of stale queries re-issued to the leader
+	ReadRepairs uint64
+
+	// FallbackQueries is the number of queries served by a replica because the leader was unavailable
+	FallbackQueries uint64
+
+	// WatchStreams is the number of watch streams currently open on the session
+	WatchStreams int64
+}
+
+// Stats returns a snapshot of the session's operation counts
+func (s *Session) Stats() Stats {
+	return Stats{
+		Commands:        atomic.LoadUint64(&s.stats.Commands),
+		Queries:         atomic.LoadUint64(&s.stats.Queries),
+		LeaderQueries:   atomic.LoadUint64(&s.stats.LeaderQueries),
+		ReplicaQueries:  atomic.LoadUint64(&s.stats.ReplicaQueries),
+		Retries:         atomic.LoadUint64(&s.stats.Retries),
+		Reconnects:      atomic.LoadUint64(&s.stats.Reconnects),
+		ReadRepairs:     atomic.LoadUint64(&s.stats.ReadRepairs),
+		FallbackQueries: atomic.LoadUint64(&s.stats.FallbackQueries),
+		WatchStreams:    atomic.LoadInt64(&s.stats.WatchStreams),
+	}
+}
+
+// recordQuery records whether a query was served by the leader or a replica
+// A query is served by a replica if the response names a leader other than the node the session is connected to.
+// The session initially connects to the partition's address, which may itself be served by the leader, so queries
+// are only attributed to replicas once the leader is known: when a response names the connected node as the
+// leader or the session has been redirected to the leader.
+// Returns a bool indicating whether the query was served by a replica.
+func (s *Session) recordQuery(responseHeader *headers.ResponseHeader) bool {
+	if responseHeader.Leader != "" {
+		if net.Address(responseHeader.Leader) == s.conns.Leader() {
+			atomic.StoreInt32(&s.leaderKnown, 1)
+		} else if atomic.LoadInt32(&s.leaderKnown) == 1 {
+			atomic.AddUint64(&s.stats.ReplicaQueries, 1)
+			return true
+		}
+	}
+	atomic.AddUint64(&s.stats.LeaderQueries, 1)
+	return false
+}
+
+// reconnect reconnects the session to the given leader, recording the reconnect
+func (s *Session) reconnect(leader string) {
+	if leader != "" {
+		atomic.StoreInt32(&s.leaderKnown, 1)
+	}
+	if s.conns.Reconnect(net.Address(leader)) {
+		atomic.AddUint64(&s.stats.Reconnects, 1)
+		s.logger.Info("Reconnected session", s.fields("leader", leader)...)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import "context"
+
+// Tracer traces session operations
+// An OpenTelemetry implementation is provided by the tracing package.
+type Tracer interface {
+	// Start starts a span for the given operation
+	// The returned context carries the span and is propagated to the operation's requests.
+	Start(ctx context.Context, op Operation) (context.Context, Span)
+}
+
+// Span is a traced session operation
+type Span interface {
+	// End ends the span, recording the error if the operation failed
+	// The completed operation is passed to the span since whether it was served by a replica and the number of
+	// times its request was resent are only known once it finishes.
+	End(op Operation, err error)
+}
+
+// noopSpan is the Span used when tracing is not configured
+type noopSpan struct{}
+
+func (noopSpan) End(op Operation, err error) {}
@@ -0,0 +1,79 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"github.com/atomix/go-client/pkg/client/session"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	primitiveKey     = attribute.Key("atomix.primitive")
+	primitiveTypeKey = attribute.Key("atomix.primitive.type")
+	operationKey     = attribute.Key("atomix.operation")
+	typeKey          = attribute.Key("atomix.operation.type")
+	partitionKey     = attribute.Key("atomix.partition")
+	replicaKey       = attribute.Key("atomix.operation.replica")
+	retriesKey       = attribute.Key("atomix.operation.retries")
+)
+
+// NewTracer returns a session Tracer that records session operations as OpenTelemetry spans
+// Spans are started as children of any span carried by the operation's context. Whether a query was served by a
+// replica and the number of retried requests are set on the span when the operation ends.
+func NewTracer(tracer trace.Tracer) session.Tracer {
+	return &otelTracer{
+		tracer: tracer,
+	}
+}
+
+// otelTracer is an OpenTelemetry implementation of session.Tracer
+type otelTracer struct {
+	tracer trace.Tracer
+}
+
+func (t *otelTracer) Start(ctx context.Context, op session.Operation) (context.Context, session.Span) {
+	ctx, span := t.tracer.Start(ctx, op.Name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			primitiveKey.String(op.Primitive),
+			primitiveTypeKey.String(string(op.PrimitiveType)),
+			operationKey.String(op.Name),
+			typeKey.String(string(op.Type)),
+			partitionKey.String(string(op.Partition))))
+	return ctx, &otelSpan{
+		span: span,
+	}
+}
+
+// otelSpan is an OpenTelemetry implementation of session.Span
+type otelSpan struct {
+	span trace.Span
+}
+
+func (s *otelSpan) End(op session.Operation, err error) {
+	s.span.SetAttributes(
+		replicaKey.Bool(op.Replica),
+		retriesKey.Int(op.Retries))
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	} else {
+		s.span.SetStatus(codes.Ok, "")
+	}
+	s.span.End()
+}
@@ -0,0 +1,110 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// ErrTooManyWatches is returned when opening a watch stream would exceed the WatchLimiter's limit
+var ErrTooManyWatches = errors.New("too many watch streams")
+
+// WatchPolicy is the policy applied to new watch streams when the WatchLimiter's limit is reached
+type WatchPolicy int
+
+const (
+	// WatchReject fails new watch streams with ErrTooManyWatches when the limit is reached
+	WatchReject WatchPolicy = iota
+
+	// WatchBlock blocks new watch streams until another stream is closed or the context is canceled
+	WatchBlock
+)
+
+// NewWatchLimiter returns a WatchLimiter allowing at most max concurrent watch streams
+func NewWatchLimiter(max int, policy WatchPolicy) *WatchLimiter {
+	return &WatchLimiter{
+		slots:  make(chan struct{}, max),
+		policy: policy,
+	}
+}
+
+// WatchLimiter limits the number of concurrent watch streams across the sessions it's shared by
+type WatchLimiter struct {
+	slots  chan struct{}
+	policy WatchPolicy
+}
+
+// Streams returns the number of open watch streams
+func (l *WatchLimiter) Streams() int {
+	return len(l.slots)
+}
+
+// acquire acquires a slot for a watch stream according to the limiter's policy
+func (l *WatchLimiter) acquire(ctx context.Context) error {
+	if l.policy == WatchBlock {
+		select {
+		case l.slots <- struct{}{}:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+		return ErrTooManyWatches
+	}
+}
+
+// release releases a watch stream's slot
+func (l *WatchLimiter) release() {
+	<-l.slots
+}
+
+// WithWatchLimiter returns a session Option to limit the session's watch streams with the given WatchLimiter
+// The limiter may be shared by many sessions to bound the total number of watch streams opened by a process.
+func WithWatchLimiter(limiter *WatchLimiter) Option {
+	return watchLimiterOption{limiter: limiter}
+}
+
+type watchLimiterOption struct {
+	limiter *WatchLimiter
+}
+
+func (o watchLimiterOption) prepare(options *options) {
+	options.watches = o.limiter
+}
+
+// openWatch acquires a slot from the session's WatchLimiter for a new watch stream
+func (s *Session) openWatch(ctx context.Context) error {
+	if s.watches != nil {
+		if err := s.watches.acquire(ctx); err != nil {
+			return err
+		}
+	}
+	atomic.AddInt64(&s.stats.WatchStreams, 1)
+	return nil
+}
+
+// closeWatch releases a closed watch stream's slot
+func (s *Session) closeWatch() {
+	atomic.AddInt64(&s.stats.WatchStreams, -1)
+	if s.watches != nil {
+		s.watches.release()
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package set
+
+import (
+	"context"
+)
+
+// Diff computes the symmetric difference of the given sets
+// Returns the elements only in a and the elements only in b, each in sorted order. Diff sorts in memory: each set
+// is read in full and sorted on the client before the two are merged, so both sets are held in memory at once.
+// The sets are not read atomically, so concurrent changes to either set may or may not be reflected in the
+// difference.
+func Diff(ctx context.Context, a, b Set) ([]string, []string, error) {
+	aCh := make(chan string)
+	if err := a.Elements(ctx, aCh, WithSorted()); err != nil {
+		go drain(aCh)
+		return nil, nil, err
+	}
+	bCh := make(chan string)
+	if err := b.Elements(ctx, bCh, WithSorted()); err != nil {
+		go drain(aCh)
+		go drain(bCh)
+		return nil, nil, err
+	}
+
+	onlyA := make([]string, 0)
+	onlyB := make([]string, 0)
+	aValue, aOK := <-aCh
+	bValue, bOK := <-bCh
+	for aOK && bOK {
+		switch {
+		case aValue < bValue:
+			onlyA = append(onlyA, aValue)
+			aValue, aOK = <-aCh
+		case bValue < aValue:
+			onlyB = append(onlyB, bValue)
+			bValue, bOK = <-bCh
+		default:
+			aValue, aOK = <-aCh
+			bValue, bOK = <-bCh
+		}
+	}
+	for ; aOK; aValue, aOK = <-aCh {
+		onlyA = append(onlyA, aValue)
+	}
+	for ; bOK; bValue, bOK = <-bCh {
+		onlyB = append(onlyB, bValue)
+	}
+	return onlyA, onlyB, nil
+}
+
+// Union adds the elements of each of the source sets to the destination set
+// Each source set is streamed and its elements added to the destination as they're read. If an element fails to
+// be added, the union stops and the error is returned. Elements added before the error remain in the destination.
+func Union(ctx context.Context, dst Set, srcs ...Set) error {
+	for _, src := range srcs {
+		ch := make(chan string)
+		if err := src.Elements(ctx, ch); err != nil {
+			go drain(ch)
+			return err
+		}
+		for element := range ch {
+			if _, err := dst.Add(ctx, element); err != nil {
+				go drain(ch)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// drain discards the remaining elements in the given channel to release the stream
+// Only channels passed to Elements may be drained, since Elements closes them even if it returns an error.
+func drain(ch <-chan string) {
+	for range ch {
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package set
+
+import (
+	"context"
+	api "github.com/atomix/api/proto/atomix/set"
+	"github.com/atomix/go-client/pkg/client/primitive"
+	"github.com/atomix/go-client/pkg/client/session"
+	"github.com/atomix/go-client/pkg/client/util"
+	"sort"
+	"sync"
+)
+
+// fakeWatchBuffer is the number of events buffered for each watcher of a fake set unless WithBuffer is provided
+const fakeWatchBuffer = 1000
+
+// NewFake returns an in-memory Set for testing code that depends on Set
+// The fake implements the semantics of Set without sessions or a server. Changes are published to watchers, and
+// Clear publishes an EventRemoved event for each removed value. The fake has no sessions.
+func NewFake(name primitive.Name) Set {
+	return &fakeSet{
+		Base:   primitive.NewBase(Type, name),
+		values: make(map[string]bool),
+	}
+}
+
+// fakeSet is an in-memory implementation of Set
+type fakeSet struct {
+	primitive.Base
+	values   map[string]bool
+	watchers []*fakeWatcher
+	mu       sync.Mutex
+}
+
+// fakeWatcher is a watcher of a fake set
+type fakeWatcher struct {
+	options *watchOptions
+	buffer  *util.EventBuffer
+}
+
+func (s *fakeSet) Sessions() []*session.Session {
+	return nil
+}
+
+func (s *fakeSet) Add(ctx context.Context, value string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.values[value] {
+		return false, nil
+	}
+	s.values[value] = true
+	s.publish(&Event{Type: EventAdded, Value: value})
+	return true, nil
+}
+
+func (s *fakeSet) Remove(ctx context.Context, value string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.values[value] {
+		return false, nil
+	}
+	delete(s.values, value)
+	s.publish(&Event{Type: EventRemoved, Value: value})
+	return true, nil
+}
+
+func (s *fakeSet) Contains(ctx context.Context, value string, opts ...ContainsOption) (bool, error) {
+	if options := newContainsOptions(opts...); options.stale != nil {
+		*options.stale = false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.values[value], nil
+}
+
+func (s *fakeSet) ContainsAny(ctx context.Context, values ...string) (bool, error) {
+	return containsAny(ctx, s, values)
+}
+
+func (s *fakeSet) Len(ctx context.Context, opts ...LenOption) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.values), nil
+}
+
+func (s *fakeSet) SizeWithin(ctx context.Context, tolerance float64) (int, error) {
+	return s.Len(ctx)
+}
+
+func (s *fakeSet) WaitForSize(ctx context.Context, target int, cmp Comparison) error {
+	return waitForSize(ctx, s, target, cmp)
+}
+
+func (s *fakeSet) Clear(ctx context.Context, opts ...ClearOption) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, value := range s.elements() {
+		delete(s.values, value)
+		s.publish(&Event{Type: EventRemoved, Value: value})
+	}
+	return nil
+}
+
+func (s *fakeSet) Elements(ctx context.Context, ch chan<- string, opts ...ElementsOption) error {
+	s.mu.Lock()
+	elements := s.elements()
+	s.mu.Unlock()
+
+	if options := newElementsOptions(opts...); options.sorted {
+		sort.Strings(elements)
+	}
+	go func() {
+		for _, element := range elements {
+			ch <- element
+		}
+		close(ch)
+	}()
+	return nil
+}
+
+// elements returns the values in the set
+// The caller must hold the set's lock.
+func (s *fakeSet) elements() []string {
+	elements := make([]string, 0, len(s.values))
+	for value := range s.values {
+		elements = append(elements, value)
+	}
+	return elements
+}
+
+func (s *fakeSet) Watch(ctx context.Context, ch chan<- *Event, opts ...WatchOption) error {
+	request := &api.EventRequest{}
+	for _, opt := range opts {
+		opt.beforeWatch(request)
+	}
+	options := newWatchOptions(opts)
+	size, policy := fakeWatchBuffer, util.OverflowBlock
+	if options.bufferSize > 0 {
+		size, policy = options.bufferSize, options.overflow
+	}
+	watcher := &fakeWatcher{
+		options: options,
+		buffer: util.NewEventBuffer(size, policy, func(event interface{}) {
+			deliver(ch, event.(*Event), options.stats)
+		}, options.stats.DroppedFunc(options.dropped)),
+	}
+
+	s.mu.Lock()
+	if request.Replay && options.accepts(EventNone) {
+		for _, value := range s.elements() {
+			watcher.buffer.Push(&Event{Type: EventNone, Value: value})
+		}
+	}
+	s.watchers = append(s.watchers, watcher)
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.mu.Lock()
+		for i, w := range s.watchers {
+			if w == watcher {
+				s.watchers = append(s.watchers[:i], s.watchers[i+1:]...)
+				break
+			}
+		}
+		s.mu.Unlock()
+		watcher.buffer.Close()
+		close(ch)
+	}()
+	return nil
+}
+
+// publish publishes an event to the set's watchers
+// The caller must hold the set's lock.
+func (s *fakeSet) publish(event *Event) {
+	for _, watcher := range s.watchers {
+		if watcher.options.accepts(event.Type) {
+			watcher.buffer.Push(event)
+		}
+	}
+}
+
+func (s *fakeSet) Close() error {
+	return nil
+}
+
+func (s *fakeSet) Delete() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values = make(map[string]bool)
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package set
+
+import (
+	"context"
+	"github.com/atomix/go-client/pkg/client/primitive"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestFakeSet(t *testing.T) {
+	set := NewFake(primitive.NewName("default", "test", "default", "test"))
+	assert.Equal(t, Type, set.Metadata().Type)
+	assert.Len(t, set.Sessions(), 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan *Event)
+	err := set.Watch(ctx, ch)
+	assert.NoError(t, err)
+
+	added, err := set.Add(context.TODO(), "foo")
+	assert.NoError(t, err)
+	assert.True(t, added)
+	added, err = set.Add(context.TODO(), "foo")
+	assert.NoError(t, err)
+	assert.False(t, added)
+	added, err = set.Add(context.TODO(), "bar")
+	assert.NoError(t, err)
+	assert.True(t, added)
+
+	contains, err := set.Contains(context.TODO(), "foo")
+	assert.NoError(t, err)
+	assert.True(t, contains)
+	contains, err = set.Contains(context.TODO(), "baz")
+	assert.NoError(t, err)
+	assert.False(t, contains)
+
+	size, err := set.Len(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, size)
+	size, err = set.SizeWithin(context.TODO(), .5)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, size)
+
+	elements := make(chan string)
+	err = set.Elements(context.TODO(), elements, WithSorted())
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", <-elements)
+	assert.Equal(t, "foo", <-elements)
+	_, ok := <-elements
+	assert.False(t, ok)
+
+	removed, err := set.Remove(context.TODO(), "foo")
+	assert.NoError(t, err)
+	assert.True(t, removed)
+	removed, err = set.Remove(context.TODO(), "foo")
+	assert.NoError(t, err)
+	assert.False(t, removed)
+
+	err = set.Clear(context.TODO())
+	assert.NoError(t, err)
+	size, err = set.Len(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, size)
+
+	assert.Equal(t, &Event{Type: EventAdded, Value: "foo"}, <-ch)
+	assert.Equal(t, &Event{Type: EventAdded, Value: "bar"}, <-ch)
+	assert.Equal(t, &Event{Type: EventRemoved, Value: "foo"}, <-ch)
+	assert.Equal(t, &Event{Type: EventRemoved, Value: "bar"}, <-ch)
+
+	cancel()
+	_, ok = <-ch
+	assert.False(t, ok)
+}
+
+func TestFakeSetWatchOptions(t *testing.T) {
+	set := NewFake(primitive.NewName("default", "test", "default", "test"))
+	_, err := set.Add(context.TODO(), "foo")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := make(chan *Event)
+	err = set.Watch(ctx, ch, WithReplay())
+	assert.NoError(t, err)
+	assert.Equal(t, &Event{Type: EventNone, Value: "foo"}, <-ch)
+
+	filtered := make(chan *Event)
+	err = set.Watch(ctx, filtered, WithFilter(EventRemoved))
+	assert.NoError(t, err)
+
+	_, err = set.Add(context.TODO(), "bar")
+	assert.NoError(t, err)
+	_, err = set.Remove(context.TODO(), "bar")
+	assert.NoError(t, err)
+	assert.Equal(t, &Event{Type: EventAdded, Value: "bar"}, <-ch)
+	assert.Equal(t, &Event{Type: EventRemoved, Value: "bar"}, <-ch)
+	assert.Equal(t, &Event{Type: EventRemoved, Value: "bar"}, <-filtered)
+
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer waitCancel()
+	done := make(chan error)
+	go func() {
+		done <- set.WaitForSize(waitCtx, 3, Gte)
+	}()
+	for _, value := range []string{"a", "b", "c"} {
+		_, err = set.Add(context.TODO(), value)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, <-done)
+}
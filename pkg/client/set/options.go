@@ -16,6 +16,8 @@ package set
 
 import (
 	api "github.com/atomix/api/proto/atomix/set"
+	"github.com/atomix/go-client/pkg/client/util"
+	"sync/atomic"
 )
 
 // WatchOption is an option for set Watch calls
@@ -38,3 +40,272 @@ func (o replayOption) beforeWatch(request *api.EventRequest) {
 func (o replayOption) afterWatch(response *api.EventResponse) {
 
 }
+
+// watchOptions is the client-side options for set Watch calls
+type watchOptions struct {
+	bufferSize int
+	overflow   util.OverflowPolicy
+	dropped    func()
+	types      map[EventType]bool
+	errors     chan<- *WatchError
+	stats      *util.WatchStats
+}
+
+// accepts returns whether events of the given type pass the watch's filter
+func (o *watchOptions) accepts(t EventType) bool {
+	return o.types == nil || o.types[t]
+}
+
+// clientWatchOption is a Watch option that's applied by the client rather than sent to the partitions
+type clientWatchOption interface {
+	applyWatch(options *watchOptions)
+}
+
+// newWatchOptions returns the client-side options for the given Watch options
+func newWatchOptions(opts []WatchOption) *watchOptions {
+	options := &watchOptions{}
+	for _, opt := range opts {
+		if clientOpt, ok := opt.(clientWatchOption); ok {
+			clientOpt.applyWatch(options)
+		}
+	}
+	return options
+}
+
+// WithBuffer returns a Watch option to buffer up to size events between the partition streams and the channel
+// By default events are sent directly to the channel, so a slow consumer blocks the streams. With a buffer, events
+// that arrive while the buffer is full are handled according to the given policy: util.OverflowBlock blocks the
+// streams as before, while util.OverflowDropOldest and util.OverflowDropNewest keep the streams flowing at the cost
+// of dropping events. Use WithDropCounter to detect dropped events.
+func WithBuffer(size int, policy util.OverflowPolicy) WatchOption {
+	return bufferOption{size: size, policy: policy}
+}
+
+type bufferOption struct {
+	size   int
+	policy util.OverflowPolicy
+}
+
+func (o bufferOption) beforeWatch(request *api.EventRequest) {
+
+}
+
+func (o bufferOption) afterWatch(response *api.EventResponse) {
+
+}
+
+func (o bufferOption) applyWatch(options *watchOptions) {
+	options.bufferSize = o.size
+	options.overflow = o.policy
+}
+
+// WithFilter returns a Watch option to only deliver events of the given types
+// The set service cannot filter events, so events are filtered by the client before they reach the channel.
+func WithFilter(types ...EventType) WatchOption {
+	return filterOption{types: types}
+}
+
+type filterOption struct {
+	types []EventType
+}
+
+func (o filterOption) beforeWatch(request *api.EventRequest) {
+
+}
+
+func (o filterOption) afterWatch(response *api.EventResponse) {
+
+}
+
+func (o filterOption) applyWatch(options *watchOptions) {
+	options.types = make(map[EventType]bool)
+	for _, t := range o.types {
+		options.types[t] = true
+	}
+}
+
+// WithDropCounter returns a Watch option to atomically increment the given counter for each dropped event
+func WithDropCounter(counter *uint64) WatchOption {
+	return dropCounterOption{counter: counter}
+}
+
+type dropCounterOption struct {
+	counter *uint64
+}
+
+func (o dropCounterOption) beforeWatch(request *api.EventRequest) {
+
+}
+
+func (o dropCounterOption) afterWatch(response *api.EventResponse) {
+
+}
+
+func (o dropCounterOption) applyWatch(options *watchOptions) {
+	options.dropped = func() {
+		atomic.AddUint64(o.counter, 1)
+	}
+}
+
+// ContainsOption is an option for set Contains calls
+type ContainsOption interface {
+	applyContains(options *containsOptions)
+}
+
+// containsOptions is the options for set Contains calls
+type containsOptions struct {
+	stale *bool
+}
+
+// newContainsOptions applies the given Contains options
+func newContainsOptions(opts ...ContainsOption) *containsOptions {
+	options := &containsOptions{}
+	for _, opt := range opts {
+		opt.applyContains(options)
+	}
+	return options
+}
+
+// WithStaleFallback returns a Contains option to read from a replica if the partition's leader is unavailable
+// Replicas are configured with session.WithReplicas. If the leader is unavailable, membership is read from a
+// replica without waiting for the replica to catch up with the session's writes, so the result may not reflect a
+// preceding Add or Remove, and stale is set to true. Otherwise, stale is set to false. Without this option,
+// Contains never reads from a replica while the leader is unavailable.
+func WithStaleFallback(stale *bool) ContainsOption {
+	return staleFallbackOption{stale: stale}
+}
+
+type staleFallbackOption struct {
+	stale *bool
+}
+
+func (o staleFallbackOption) applyContains(options *containsOptions) {
+	options.stale = o.stale
+}
+
+// LenOption is an option for set Len calls
+type LenOption interface {
+	applyLen(options *lenOptions)
+}
+
+// lenOptions is the options for set Len calls
+type lenOptions struct {
+	exact bool
+}
+
+// Exact returns a Len option to count the distinct elements in the set
+// By default, the size of the set is computed by summing the sizes of its partitions, which requires a single
+// request to each partition but over-counts elements that are transiently stored in more than one partition.
+// An exact count streams every element of the set through a deduplicating scan, which is accurate even while
+// elements are moved between partitions but requires all elements to be read and buffered in memory.
+func Exact() LenOption {
+	return exactOption{}
+}
+
+type exactOption struct{}
+
+func (o exactOption) applyLen(options *lenOptions) {
+	options.exact = true
+}
+
+// WithWatchStats returns a Watch option to track the delivery of the watch's events in the given stats
+// The stats count the events delivered to the channel and dropped by the buffer, and record the time of the last
+// delivered event. Set events are not versioned, so the last version is always 0. The stats are updated atomically
+// and may be read while the watch is running. They're also included in the sessions' logs when a partition's
+// stream fails.
+func WithWatchStats(stats *util.WatchStats) WatchOption {
+	return watchStatsOption{stats: stats}
+}
+
+type watchStatsOption struct {
+	stats *util.WatchStats
+}
+
+func (o watchStatsOption) beforeWatch(request *api.EventRequest) {
+
+}
+
+func (o watchStatsOption) afterWatch(response *api.EventResponse) {
+
+}
+
+func (o watchStatsOption) applyWatch(options *watchOptions) {
+	options.stats = o.stats
+}
+
+// WithWatchErrors returns a Watch option to report partitions whose watch stream fails to the given channel
+// Streams that fail with transient errors are re-established by the partition's session, so a failure is only
+// reported once a partition's stream has closed before the watch's context was canceled. Events from the failed
+// partition are no longer delivered, while the remaining partitions continue to deliver events. A failed partition
+// may have missed events, so the consumer should resync with the set, e.g. by watching again using WithReplay.
+func WithWatchErrors(ch chan<- *WatchError) WatchOption {
+	return watchErrorsOption{ch: ch}
+}
+
+type watchErrorsOption struct {
+	ch chan<- *WatchError
+}
+
+func (o watchErrorsOption) beforeWatch(request *api.EventRequest) {
+
+}
+
+func (o watchErrorsOption) afterWatch(response *api.EventResponse) {
+
+}
+
+func (o watchErrorsOption) applyWatch(options *watchOptions) {
+	options.errors = o.ch
+}
+
+// ElementsOption is an option for set Elements calls
+type ElementsOption interface {
+	applyElements(options *elementsOptions)
+}
+
+// elementsOptions is the options for set Elements calls
+type elementsOptions struct {
+	sorted bool
+}
+
+// WithSorted returns an Elements option to deliver elements in sorted order
+// By default, elements are streamed in no particular order as they're read from each partition. Sorting
+// requires all elements of the set to be buffered in memory before the first element is delivered, so it
+// should be used with care for large sets.
+func WithSorted() ElementsOption {
+	return sortedOption{}
+}
+
+type sortedOption struct{}
+
+func (o sortedOption) applyElements(options *elementsOptions) {
+	options.sorted = true
+}
+
+// ClearOption is an option for set Clear calls
+type ClearOption interface {
+	applyClear(options *clearOptions)
+}
+
+// clearOptions is the options for set Clear calls
+type clearOptions struct {
+	barrier bool
+}
+
+// WithBarrier returns a Clear option to make the clear appear atomic to concurrent operations
+// Partitions are cleared independently, so without a barrier a concurrent Add may be applied to an already
+// cleared partition while other partitions still hold old values. With a barrier, operations issued through
+// the same Set instance are blocked until every partition has been cleared, and scans in progress finish
+// reading their partitions before the clear begins.
+// The barrier is client-local: it is a lock held by this Set instance in this process, not a server-side
+// operation. It does not order the clear with respect to writes from other clients, other processes, or
+// other Set instances, which may still be applied to partially cleared partitions.
+func WithBarrier() ClearOption {
+	return barrierOption{}
+}
+
+type barrierOption struct{}
+
+func (o barrierOption) applyClear(options *clearOptions) {
+	options.barrier = true
+}
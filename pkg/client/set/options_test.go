@@ -16,6 +16,7 @@ package set
 
 import (
 	api "github.com/atomix/api/proto/atomix/set"
+	"github.com/atomix/go-client/pkg/client/util"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -25,4 +26,35 @@ func TestOptions(t *testing.T) {
 	assert.False(t, request.Replay)
 	WithReplay().beforeWatch(request)
 	assert.True(t, request.Replay)
+
+	options := &elementsOptions{}
+	assert.False(t, options.sorted)
+	WithSorted().applyElements(options)
+	assert.True(t, options.sorted)
+
+	containsOptions := newContainsOptions()
+	assert.Nil(t, containsOptions.stale)
+	stale := true
+	containsOptions = newContainsOptions(WithStaleFallback(&stale))
+	assert.Equal(t, &stale, containsOptions.stale)
+
+	clearOptions := &clearOptions{}
+	assert.False(t, clearOptions.barrier)
+	WithBarrier().applyClear(clearOptions)
+	assert.True(t, clearOptions.barrier)
+
+	watchOptions := newWatchOptions([]WatchOption{WithReplay()})
+	assert.Equal(t, 0, watchOptions.bufferSize)
+	assert.Nil(t, watchOptions.dropped)
+	var dropped uint64
+	watchOptions = newWatchOptions([]WatchOption{WithReplay(), WithBuffer(10, util.OverflowDropOldest), WithDropCounter(&dropped)})
+	assert.Equal(t, 10, watchOptions.bufferSize)
+	assert.Equal(t, util.OverflowDropOldest, watchOptions.overflow)
+	watchOptions.dropped()
+	assert.Equal(t, uint64(1), dropped)
+
+	assert.Nil(t, watchOptions.errors)
+	errCh := make(chan *WatchError)
+	watchOptions = newWatchOptions([]WatchOption{WithWatchErrors(errCh)})
+	assert.Equal(t, (chan<- *WatchError)(errCh), watchOptions.errors)
 }
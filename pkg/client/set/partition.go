@@ -25,28 +25,39 @@ import (
 	"google.golang.org/grpc"
 )
 
+// Operation names used to identify set operations in traces and metrics
+const (
+	addOp      = "set.Add"
+	removeOp   = "set.Remove"
+	containsOp = "set.Contains"
+	lenOp      = "set.Len"
+	clearOp    = "set.Clear"
+	elementsOp = "set.Elements"
+	watchOp    = "set.Watch"
+)
+
 func newPartition(ctx context.Context, address net.Address, name primitive.Name, opts ...session.Option) (Set, error) {
 	sess, err := session.New(ctx, name, address, &sessionHandler{}, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return &setPartition{
-		name:    name,
+		Base:    primitive.NewBase(Type, name),
 		session: sess,
 	}, nil
 }
 
 type setPartition struct {
-	name    primitive.Name
+	primitive.Base
 	session *session.Session
 }
 
-func (s *setPartition) Name() primitive.Name {
-	return s.name
+func (s *setPartition) Sessions() []*session.Session {
+	return []*session.Session{s.session}
 }
 
 func (s *setPartition) Add(ctx context.Context, value string) (bool, error) {
-	r, err := s.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	r, err := s.session.DoCommand(ctx, addOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewSetServiceClient(conn)
 		request := &api.AddRequest{
 			Header: header,
@@ -70,7 +81,7 @@ func (s *setPartition) Add(ctx context.Context, value string) (bool, error) {
 }
 
 func (s *setPartition) Remove(ctx context.Context, value string) (bool, error) {
-	r, err := s.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	r, err := s.session.DoCommand(ctx, removeOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewSetServiceClient(conn)
 		request := &api.RemoveRequest{
 			Header: header,
@@ -93,8 +104,17 @@ func (s *setPartition) Remove(ctx context.Context, value string) (bool, error) {
 	return response.Removed, nil
 }
 
-func (s *setPartition) Contains(ctx context.Context, value string) (bool, error) {
-	response, err := s.session.DoQuery(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+func (s *setPartition) Contains(ctx context.Context, value string, opts ...ContainsOption) (bool, error) {
+	options := newContainsOptions(opts...)
+	query := s.session.DoQuery
+	if options.stale != nil {
+		query = func(ctx context.Context, name string, f func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error)) (interface{}, error) {
+			response, stale, err := s.session.DoQueryWithFallback(ctx, name, f)
+			*options.stale = stale
+			return response, err
+		}
+	}
+	response, err := query(ctx, containsOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewSetServiceClient(conn)
 		request := &api.ContainsRequest{
 			Header: header,
@@ -112,8 +132,27 @@ func (s *setPartition) Contains(ctx context.Context, value string) (bool, error)
 	return response.(*api.ContainsResponse).Contains, nil
 }
 
-func (s *setPartition) Len(ctx context.Context) (int, error) {
-	response, err := s.session.DoQuery(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+// ContainsAny checks each of the given values in order until one is found
+// The set service has no batch membership query, so each value is checked with a separate Contains query.
+func (s *setPartition) ContainsAny(ctx context.Context, values ...string) (bool, error) {
+	return containsAny(ctx, s, values)
+}
+
+// containsAny checks each of the given values with Contains until one is found or the context is canceled
+func containsAny(ctx context.Context, s Set, values []string) (bool, error) {
+	for _, value := range values {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		if contains, err := s.Contains(ctx, value); err != nil || contains {
+			return contains, err
+		}
+	}
+	return false, nil
+}
+
+func (s *setPartition) Len(ctx context.Context, opts ...LenOption) (int, error) {
+	response, err := s.session.DoQuery(ctx, lenOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewSetServiceClient(conn)
 		request := &api.SizeRequest{
 			Header: header,
@@ -130,8 +169,12 @@ func (s *setPartition) Len(ctx context.Context) (int, error) {
 	return int(response.(*api.SizeResponse).Size_), nil
 }
 
-func (s *setPartition) Clear(ctx context.Context) error {
-	_, err := s.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+func (s *setPartition) SizeWithin(ctx context.Context, tolerance float64) (int, error) {
+	return s.Len(ctx)
+}
+
+func (s *setPartition) Clear(ctx context.Context, opts ...ClearOption) error {
+	_, err := s.session.DoCommand(ctx, clearOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewSetServiceClient(conn)
 		request := &api.ClearRequest{
 			Header: header,
@@ -145,8 +188,8 @@ func (s *setPartition) Clear(ctx context.Context) error {
 	return err
 }
 
-func (s *setPartition) Elements(ctx context.Context, ch chan<- string) error {
-	stream, err := s.session.DoQueryStream(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
+func (s *setPartition) Elements(ctx context.Context, ch chan<- string, opts ...ElementsOption) error {
+	stream, err := s.session.DoQueryStream(ctx, elementsOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
 		client := api.NewSetServiceClient(conn)
 		request := &api.IterateRequest{
 			Header: header,
@@ -160,9 +203,17 @@ func (s *setPartition) Elements(ctx context.Context, ch chan<- string) error {
 		return response.Header, response, nil
 	})
 	if err != nil {
+		close(ch)
 		return err
 	}
 
+	options := newElementsOptions(opts...)
+	if options.sorted {
+		elementsCh := make(chan string)
+		go sortElements(elementsCh, ch)
+		ch = elementsCh
+	}
+
 	go func() {
 		defer close(ch)
 		for event := range stream {
@@ -173,7 +224,7 @@ func (s *setPartition) Elements(ctx context.Context, ch chan<- string) error {
 }
 
 func (s *setPartition) Watch(ctx context.Context, ch chan<- *Event, opts ...WatchOption) error {
-	stream, err := s.session.DoCommandStream(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
+	stream, err := s.session.DoCommandStream(ctx, watchOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
 		client := api.NewSetServiceClient(conn)
 		request := &api.EventRequest{
 			Header: header,
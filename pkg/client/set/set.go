@@ -16,16 +16,58 @@ package set
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/atomix/go-client/pkg/client/primitive"
 	"github.com/atomix/go-client/pkg/client/session"
 	"github.com/atomix/go-client/pkg/client/util"
 	"github.com/atomix/go-client/pkg/client/util/net"
+	"math"
+	"sort"
 	"sync"
+	"sync/atomic"
 )
 
 // Type is the set type
 const Type primitive.Type = "Set"
 
+// ErrAllPartitionsUnavailable is returned by operations on the set when every partition of the set failed
+// Errors from operations that failed on only some of the set's partitions are returned as is.
+var ErrAllPartitionsUnavailable = util.ErrAllPartitionsUnavailable
+
+// ErrNotFound is returned by Delete when the primitive does not exist
+var ErrNotFound = primitive.ErrNotFound
+
+// ErrWatchClosed is reported by WithWatchErrors when a partition's watch stream is closed
+// If the cause of the closure is known, the reported error wraps the cause and matches ErrWatchClosed with errors.Is.
+var ErrWatchClosed = errors.New("partition watch stream closed")
+
+// newWatchClosedError returns the error reported for a partition watch stream closed by the given cause
+// The cause is nil if the stream was closed without an error.
+func newWatchClosedError(cause error) error {
+	if cause == nil {
+		return ErrWatchClosed
+	}
+	return &watchClosedError{cause: cause}
+}
+
+// watchClosedError is a partition watch stream closure with a known cause
+type watchClosedError struct {
+	cause error
+}
+
+func (e *watchClosedError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrWatchClosed, e.cause)
+}
+
+func (e *watchClosedError) Is(target error) bool {
+	return target == ErrWatchClosed
+}
+
+func (e *watchClosedError) Unwrap() error {
+	return e.cause
+}
+
 // Client provides an API for creating Sets
 type Client interface {
 	// GetSet gets the Set instance of the given name
@@ -38,7 +80,14 @@ type Client interface {
 type Set interface {
 	primitive.Primitive
 
+	// Sessions returns the sessions used by the primitive, one for each partition in which it's stored
+	Sessions() []*session.Session
+
 	// Add adds a value to the set
+	// A bool indicating whether the value was added will be returned. The value is added atomically by the
+	// partition that owns it, so the check for an existing value and the add cannot be interleaved with other
+	// operations on the value. If the same value is added concurrently, exactly one caller is told the value was
+	// added, and the others observe the value as already present in the order their adds were committed.
 	Add(ctx context.Context, value string) (bool, error)
 
 	// Remove removes a value from the set
@@ -46,16 +95,45 @@ type Set interface {
 	Remove(ctx context.Context, value string) (bool, error)
 
 	// Contains returns a bool indicating whether the set contains the given value
-	Contains(ctx context.Context, value string) (bool, error)
+	Contains(ctx context.Context, value string, opts ...ContainsOption) (bool, error)
+
+	// ContainsAny returns a bool indicating whether the set contains at least one of the given values
+	// ContainsAny is a convenience for checking values with Contains: the set service has no batch membership
+	// query, so one Contains query is still sent per value checked. Values are grouped by partition and the
+	// partitions are queried concurrently. Once any value is found, the remaining queries are canceled.
+	ContainsAny(ctx context.Context, values ...string) (bool, error)
 
 	// Len gets the set size in number of elements
-	Len(ctx context.Context) (int, error)
+	// By default the size is the sum of the partitions' sizes. If an element is transiently stored in more than
+	// one partition, e.g. while partitions are rebalanced, it's counted more than once. The Exact option counts
+	// distinct elements at the cost of scanning the whole set.
+	Len(ctx context.Context, opts ...LenOption) (int, error)
+
+	// SizeWithin estimates the set size to within the given relative tolerance
+	// Rather than querying every partition, the size is extrapolated from a random sample of partitions.
+	// A tolerance of 0.05 bounds the error of the estimate to 5% of the size with high probability.
+	// The first sample is a quarter of the partitions, and the sample grows until the estimate is within the
+	// tolerance, so larger tolerances query fewer partitions. Sets with 3 or fewer partitions, and a
+	// tolerance <= 0, return the exact size as computed by Len.
+	SizeWithin(ctx context.Context, tolerance float64) (int, error)
+
+	// WaitForSize blocks until the set size compares to the target according to the given Comparison
+	// The condition is checked before watching the set, so WaitForSize returns immediately if it already holds.
+	// If the context is canceled or its deadline is exceeded before the condition holds, the context's error
+	// is returned.
+	WaitForSize(ctx context.Context, target int, cmp Comparison) error
 
 	// Clear removes all values from the set
-	Clear(ctx context.Context) error
+	// Partitions are cleared independently unless the WithBarrier option is provided. The barrier is local to
+	// this client and does not order the clear with respect to writes from other clients.
+	Clear(ctx context.Context, opts ...ClearOption) error
 
 	// Elements lists the elements in the set
-	Elements(ctx context.Context, ch chan<- string) error
+	// Elements are streamed to the given channel in no particular order unless the WithSorted option is provided.
+	// The channel is closed once the elements have been streamed, even if an error is returned. A partitioned scan
+	// that fails may still deliver the elements of the partitions that succeeded, so the caller must drain the
+	// channel in either case.
+	Elements(ctx context.Context, ch chan<- string, opts ...ElementsOption) error
 
 	// Watch watches the set for changes
 	// This is a non-blocking method. If the method returns without error, set events will be pushed onto
@@ -86,10 +164,31 @@ type Event struct {
 	Value string
 }
 
+// WatchError is a failure of one partition's watch stream
+type WatchError struct {
+	// Partition is the index of the partition whose stream failed
+	Partition int
+
+	// Err is the cause of the failure
+	// Err matches ErrWatchClosed with errors.Is and wraps the error that closed the stream, e.g.
+	// session.ErrReconnectLimitExceeded, if it's known.
+	Err error
+
+	// Recoverable indicates whether the partition's sessions are still open, so watching the set again may succeed
+	Recoverable bool
+}
+
+func (e *WatchError) Error() string {
+	return fmt.Sprintf("partition %d: %s", e.Partition, e.Err)
+}
+
 // New creates a new partitioned set primitive
 func New(ctx context.Context, name primitive.Name, partitions []net.Address, opts ...session.Option) (Set, error) {
-	results, err := util.ExecuteOrderedAsync(len(partitions), func(i int) (interface{}, error) {
+	// If any partition fails to open, close the partitions that were opened before returning the error
+	results, err := util.ExecuteOrderedAsyncContext(ctx, len(partitions), func(ctx context.Context, i int) (interface{}, error) {
 		return newPartition(ctx, partitions[i], name, opts...)
+	}, func(result interface{}) {
+		result.(Set).Close()
 	})
 	if err != nil {
 		return nil, err
@@ -101,23 +200,30 @@ func New(ctx context.Context, name primitive.Name, partitions []net.Address, opt
 	}
 
 	return &set{
-		name:       name,
-		partitions: sets,
+		Base:        primitive.NewBase(Type, name),
+		partitions:  sets,
+		partitioner: session.GetPartitioner(opts...),
 	}, nil
 }
 
 // set is the partitioned implementation of Set
 type set struct {
-	name       primitive.Name
-	partitions []Set
+	primitive.Base
+	partitions  []Set
+	partitioner util.Partitioner
+	barrier     sync.RWMutex
 }
 
-func (s *set) Name() primitive.Name {
-	return s.name
+func (s *set) Sessions() []*session.Session {
+	sessions := make([]*session.Session, 0, len(s.partitions))
+	for _, partition := range s.partitions {
+		sessions = append(sessions, partition.Sessions()...)
+	}
+	return sessions
 }
 
 func (s *set) getPartition(key string) (Set, error) {
-	i, err := util.GetPartitionIndex(key, len(s.partitions))
+	i, err := s.partitioner(key, len(s.partitions))
 	if err != nil {
 		return nil, err
 	}
@@ -125,6 +231,8 @@ func (s *set) getPartition(key string) (Set, error) {
 }
 
 func (s *set) Add(ctx context.Context, value string) (bool, error) {
+	s.barrier.RLock()
+	defer s.barrier.RUnlock()
 	partition, err := s.getPartition(value)
 	if err != nil {
 		return false, err
@@ -133,6 +241,8 @@ func (s *set) Add(ctx context.Context, value string) (bool, error) {
 }
 
 func (s *set) Remove(ctx context.Context, value string) (bool, error) {
+	s.barrier.RLock()
+	defer s.barrier.RUnlock()
 	partition, err := s.getPartition(value)
 	if err != nil {
 		return false, err
@@ -140,16 +250,68 @@ func (s *set) Remove(ctx context.Context, value string) (bool, error) {
 	return partition.Remove(ctx, value)
 }
 
-func (s *set) Contains(ctx context.Context, value string) (bool, error) {
+func (s *set) Contains(ctx context.Context, value string, opts ...ContainsOption) (bool, error) {
+	s.barrier.RLock()
+	defer s.barrier.RUnlock()
 	partition, err := s.getPartition(value)
 	if err != nil {
 		return false, err
 	}
-	return partition.Contains(ctx, value)
+	return partition.Contains(ctx, value, opts...)
+}
+
+func (s *set) ContainsAny(ctx context.Context, values ...string) (bool, error) {
+	s.barrier.RLock()
+	defer s.barrier.RUnlock()
+	groups := make(map[int][]string)
+	for _, value := range values {
+		i, err := s.partitioner(value, len(s.partitions))
+		if err != nil {
+			return false, err
+		}
+		groups[i] = append(groups[i], value)
+	}
+
+	// Cancel the queries still running on other partitions once a value is found
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	type containsResult struct {
+		contains bool
+		err      error
+	}
+	results := make(chan containsResult, len(groups))
+	for i, group := range groups {
+		go func(partition Set, values []string) {
+			contains, err := partition.ContainsAny(ctx, values...)
+			results <- containsResult{contains: contains, err: err}
+		}(s.partitions[i], group)
+	}
+
+	// A value found in one partition answers the query even if another partition failed
+	var err error
+	for range groups {
+		result := <-results
+		if result.contains {
+			return true, nil
+		} else if result.err != nil && err == nil {
+			err = result.err
+		}
+	}
+	return false, err
 }
 
-func (s *set) Len(ctx context.Context) (int, error) {
-	results, err := util.ExecuteAsync(len(s.partitions), func(i int) (interface{}, error) {
+func (s *set) Len(ctx context.Context, opts ...LenOption) (int, error) {
+	options := &lenOptions{}
+	for _, opt := range opts {
+		opt.applyLen(options)
+	}
+	if options.exact {
+		return s.exactLen(ctx)
+	}
+
+	s.barrier.RLock()
+	defer s.barrier.RUnlock()
+	results, err := util.ExecutePartitionsAsync(len(s.partitions), func(i int) (interface{}, error) {
 		return s.partitions[i].Len(ctx)
 	})
 	if err != nil {
@@ -163,21 +325,53 @@ func (s *set) Len(ctx context.Context) (int, error) {
 	return total, nil
 }
 
-func (s *set) Elements(ctx context.Context, ch chan<- string) error {
+// exactLen counts the distinct elements in the set by scanning all partitions
+func (s *set) exactLen(ctx context.Context) (int, error) {
+	ch := make(chan string)
+	if err := s.Elements(ctx, ch); err != nil {
+		for range ch {
+		}
+		return 0, err
+	}
+
+	elements := make(map[string]bool)
+	for element := range ch {
+		elements[element] = true
+	}
+	return len(elements), nil
+}
+
+func (s *set) Elements(ctx context.Context, ch chan<- string, opts ...ElementsOption) error {
+	options := newElementsOptions(opts...)
+	if options.sorted {
+		elementsCh := make(chan string)
+		go sortElements(elementsCh, ch)
+		ch = elementsCh
+	}
+
+	// Hold the barrier until the partitions have been scanned to prevent a clear from being applied in the middle
+	// of the scan. Elements are buffered between the partitions and the consumer so the barrier does not depend on
+	// the consumer, which may call other operations on the set while a clear is waiting for the scan to complete.
+	buffer := util.NewEventBuffer(math.MaxInt32, util.OverflowBlock, func(element interface{}) {
+		ch <- element.(string)
+	}, nil)
+	s.barrier.RLock()
 	n := len(s.partitions)
 	wg := sync.WaitGroup{}
 	wg.Add(n)
 
 	go func() {
 		wg.Wait()
+		s.barrier.RUnlock()
+		buffer.Close()
 		close(ch)
 	}()
 
-	return util.IterAsync(n, func(i int) error {
+	return util.IterPartitionsAsync(n, func(i int) error {
 		partitionCh := make(chan string)
 		go func() {
 			for kv := range partitionCh {
-				ch <- kv
+				buffer.Push(kv)
 			}
 			wg.Done()
 		}()
@@ -185,8 +379,38 @@ func (s *set) Elements(ctx context.Context, ch chan<- string) error {
 	})
 }
 
-func (s *set) Clear(ctx context.Context) error {
-	return util.IterAsync(len(s.partitions), func(i int) error {
+// newElementsOptions applies the given Elements options
+func newElementsOptions(opts ...ElementsOption) *elementsOptions {
+	options := &elementsOptions{}
+	for _, opt := range opts {
+		opt.applyElements(options)
+	}
+	return options
+}
+
+// sortElements buffers all elements from the given input channel and writes them to the output channel in sorted order
+func sortElements(in <-chan string, out chan<- string) {
+	elements := make([]string, 0)
+	for element := range in {
+		elements = append(elements, element)
+	}
+	sort.Strings(elements)
+	for _, element := range elements {
+		out <- element
+	}
+	close(out)
+}
+
+func (s *set) Clear(ctx context.Context, opts ...ClearOption) error {
+	options := &clearOptions{}
+	for _, opt := range opts {
+		opt.applyClear(options)
+	}
+	if options.barrier {
+		s.barrier.Lock()
+		defer s.barrier.Unlock()
+	}
+	return util.IterPartitionsAsync(len(s.partitions), func(i int) error {
 		return s.partitions[i].Clear(ctx)
 	})
 }
@@ -196,31 +420,97 @@ func (s *set) Watch(ctx context.Context, ch chan<- *Event, opts ...WatchOption)
 	wg := sync.WaitGroup{}
 	wg.Add(n)
 
+	options := newWatchOptions(opts)
+	if options.stats != nil {
+		ctx = util.NewWatchStatsContext(ctx, options.stats)
+	}
+	send := func(event *Event) {
+		deliver(ch, event, options.stats)
+	}
+	var buffer *util.EventBuffer
+	if options.bufferSize > 0 {
+		buffer = util.NewEventBuffer(options.bufferSize, options.overflow, func(event interface{}) {
+			deliver(ch, event.(*Event), options.stats)
+		}, options.stats.DroppedFunc(options.dropped))
+		send = func(event *Event) {
+			buffer.Push(event)
+		}
+	}
+
 	go func() {
 		wg.Wait()
+		if buffer != nil {
+			buffer.Close()
+		}
 		close(ch)
 	}()
 
-	return util.IterAsync(n, func(i int) error {
+	return util.IterPartitionsAsync(n, func(i int) error {
 		partitionCh := make(chan *Event)
+		streamErr := &util.StreamError{}
 		go func() {
 			for event := range partitionCh {
-				ch <- event
+				if options.accepts(event.Type) {
+					send(event)
+				}
+			}
+			if options.errors != nil && ctx.Err() == nil {
+				watchError := &WatchError{
+					Partition:   i,
+					Err:         newWatchClosedError(streamErr.Err()),
+					Recoverable: isOpen(s.partitions[i]),
+				}
+				select {
+				case options.errors <- watchError:
+				case <-ctx.Done():
+				}
 			}
 			wg.Done()
 		}()
-		return s.partitions[i].Watch(ctx, partitionCh, opts...)
+		if err := s.partitions[i].Watch(util.NewStreamErrorContext(ctx, streamErr), partitionCh, opts...); err != nil {
+			close(partitionCh)
+			return err
+		}
+		return nil
 	})
 }
 
+// deliver sends the event to the watch's channel, recording its delivery in the watch's stats
+func deliver(ch chan<- *Event, event *Event, stats *util.WatchStats) {
+	ch <- event
+	stats.RecordDelivered()
+}
+
+// isOpen returns whether none of the given partition's sessions have been closed
+func isOpen(partition Set) bool {
+	for _, s := range partition.Sessions() {
+		if s.State() == session.StateClosed {
+			return false
+		}
+	}
+	return true
+}
+
 func (s *set) Close() error {
-	return util.IterAsync(len(s.partitions), func(i int) error {
+	return util.IterPartitionsAsync(len(s.partitions), func(i int) error {
 		return s.partitions[i].Close()
 	})
 }
 
+// Delete deletes every partition of the set
+// Partitions that do not exist are ignored unless no partition exists, in which case ErrNotFound is returned.
 func (s *set) Delete() error {
-	return util.IterAsync(len(s.partitions), func(i int) error {
-		return s.partitions[i].Delete()
+	var notFound int32
+	err := util.IterPartitionsAsync(len(s.partitions), func(i int) error {
+		err := s.partitions[i].Delete()
+		if errors.Is(err, ErrNotFound) {
+			atomic.AddInt32(&notFound, 1)
+			return nil
+		}
+		return err
 	})
+	if err == nil && int(notFound) == len(s.partitions) {
+		return ErrNotFound
+	}
+	return err
 }
@@ -16,10 +16,19 @@ package set
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/atomix/go-client/pkg/client/primitive"
 	"github.com/atomix/go-client/pkg/client/session"
 	"github.com/atomix/go-client/pkg/client/test"
+	"github.com/atomix/go-client/pkg/client/util"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"math"
+	"math/rand"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -31,6 +40,8 @@ func TestSetOperations(t *testing.T) {
 	set, err := New(context.TODO(), name, conns, session.WithTimeout(5*time.Second))
 	assert.NoError(t, err)
 	assert.NotNil(t, set)
+	assert.Equal(t, Type, set.Metadata().Type)
+	assert.Equal(t, "test", set.Metadata().Name.Group)
 
 	size, err := set.Len(context.TODO())
 	assert.NoError(t, err)
@@ -175,3 +186,973 @@ func TestSetOperations(t *testing.T) {
 
 	test.StopTestPartitions(partitions)
 }
+
+// errUnavailable is the error returned by an unavailable testPartition
+var errUnavailable = status.Error(codes.Unavailable, "unavailable")
+
+// testPartition is a Set partition that is either available or unavailable
+type testPartition struct {
+	Set
+	available bool
+	size      int
+	queries   int32
+	elements  []string
+	release   chan struct{}
+}
+
+func (p *testPartition) Len(ctx context.Context, opts ...LenOption) (int, error) {
+	atomic.AddInt32(&p.queries, 1)
+	if !p.available {
+		return 0, errUnavailable
+	}
+	return p.size, nil
+}
+
+func (p *testPartition) ContainsAny(ctx context.Context, values ...string) (bool, error) {
+	atomic.AddInt32(&p.queries, 1)
+	if !p.available {
+		return false, errUnavailable
+	}
+	return false, nil
+}
+
+func (p *testPartition) Elements(ctx context.Context, ch chan<- string, opts ...ElementsOption) error {
+	if !p.available {
+		close(ch)
+		return errUnavailable
+	}
+	go func() {
+		for _, element := range p.elements {
+			ch <- element
+		}
+		if p.release != nil {
+			<-p.release
+		}
+		close(ch)
+	}()
+	return nil
+}
+
+func (p *testPartition) Clear(ctx context.Context, opts ...ClearOption) error {
+	if !p.available {
+		return errUnavailable
+	}
+	return nil
+}
+
+func TestSetAddConcurrent(t *testing.T) {
+	conns, partitions := test.StartTestPartitions(3)
+
+	name := primitive.NewName("default", "test", "default", "test")
+	set, err := New(context.TODO(), name, conns, session.WithTimeout(5*time.Second))
+	assert.NoError(t, err)
+	testAddConcurrent(t, set)
+
+	test.StopTestPartitions(partitions)
+}
+
+// testAddConcurrent adds the same values to the given set concurrently and verifies each value is added once
+func testAddConcurrent(t *testing.T, s Set) {
+	const adders, values = 10, 20
+	added := make([]int32, values)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < adders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < values; j++ {
+				ok, err := s.Add(context.TODO(), fmt.Sprintf("%d", j))
+				assert.NoError(t, err)
+				if ok {
+					atomic.AddInt32(&added[j], 1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for j := 0; j < values; j++ {
+		assert.Equal(t, int32(1), added[j], "value %d", j)
+	}
+	size, err := s.Len(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, values, size)
+}
+
+func TestSetPartitionsUnavailable(t *testing.T) {
+	partial := &set{
+		partitions: []Set{
+			&testPartition{available: true},
+			&testPartition{available: false},
+			&testPartition{available: true},
+		},
+	}
+
+	_, err := partial.Len(context.TODO())
+	assert.Equal(t, errUnavailable, err)
+	assert.NotEqual(t, ErrAllPartitionsUnavailable, err)
+
+	err = partial.Clear(context.TODO())
+	assert.Equal(t, errUnavailable, err)
+
+	unavailable := &set{
+		partitions: []Set{
+			&testPartition{available: false},
+			&testPartition{available: false},
+			&testPartition{available: false},
+		},
+	}
+
+	_, err = unavailable.Len(context.TODO())
+	assert.Equal(t, ErrAllPartitionsUnavailable, err)
+
+	err = unavailable.Clear(context.TODO())
+	assert.Equal(t, ErrAllPartitionsUnavailable, err)
+}
+
+func newSizedSet(sizes []int) (*set, []*testPartition) {
+	partitions := make([]*testPartition, len(sizes))
+	sets := make([]Set, len(sizes))
+	for i, size := range sizes {
+		partitions[i] = &testPartition{available: true, size: size}
+		sets[i] = partitions[i]
+	}
+	return &set{partitions: sets}, partitions
+}
+
+func assertSizeWithin(t *testing.T, sizes []int, tolerance float64) int32 {
+	s, partitions := newSizedSet(sizes)
+	exact := 0
+	for _, size := range sizes {
+		exact += size
+	}
+
+	approx, err := s.SizeWithin(context.TODO(), tolerance)
+	assert.NoError(t, err)
+	assert.True(t, math.Abs(float64(approx-exact)) <= tolerance*float64(exact), "%d not within %f of %d", approx, tolerance, exact)
+
+	var queries int32
+	for _, partition := range partitions {
+		queries += partition.queries
+	}
+	return queries
+}
+
+func TestSetSizeWithin(t *testing.T) {
+	samplePerm = rand.New(rand.NewSource(1)).Perm
+	defer func() {
+		samplePerm = rand.Perm
+	}()
+
+	uniform := make([]int, 256)
+	for i := range uniform {
+		uniform[i] = 1000 + i%10
+	}
+	queries := assertSizeWithin(t, uniform, .05)
+	assert.True(t, queries < int32(len(uniform)))
+
+	skewed := make([]int, 256)
+	for i := range skewed {
+		skewed[i] = int(1000 * math.Pow(1.02, float64(i)))
+	}
+	assertSizeWithin(t, skewed, .05)
+	assertSizeWithin(t, skewed, .2)
+	assertSizeWithin(t, skewed, .5)
+
+	zipf := make([]int, 256)
+	for i := range zipf {
+		zipf[i] = 100000 / (i + 1)
+	}
+	assertSizeWithin(t, zipf, .05)
+	assertSizeWithin(t, zipf, .2)
+
+	queries = assertSizeWithin(t, uniform, 0)
+	assert.Equal(t, int32(len(uniform)), queries)
+
+	small, _ := newSizedSet([]int{1, 2, 3})
+	size, err := small.SizeWithin(context.TODO(), .05)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, size)
+}
+
+func TestSetSizeWithinSampling(t *testing.T) {
+	samplePerm = rand.New(rand.NewSource(1)).Perm
+	defer func() {
+		samplePerm = rand.Perm
+	}()
+
+	// Sets with no more partitions than the minimum sample are sized exactly
+	sizes := make([]int, minSampleSize)
+	for i := range sizes {
+		sizes[i] = 1000 + i%10
+	}
+	queries := assertSizeWithin(t, sizes, .05)
+	assert.Equal(t, int32(minSampleSize), queries)
+
+	// Larger sets are estimated from a sample of their partitions
+	for _, n := range []int{8, 16, 32, 120} {
+		for _, tolerance := range []float64{.01, .05, .2} {
+			sizes = make([]int, n)
+			for i := range sizes {
+				sizes[i] = 1000 + i%10
+			}
+			queries = assertSizeWithin(t, sizes, tolerance)
+			assert.True(t, queries >= int32(math.Ceil(sampleFraction*float64(n))))
+			assert.True(t, queries < int32(n), "sampled %d of %d partitions", queries, n)
+		}
+	}
+
+	// Tighter tolerances sample more partitions
+	sizes = make([]int, 32)
+	for i := range sizes {
+		sizes[i] = 1000 + i%10
+	}
+	samplePerm = rand.New(rand.NewSource(1)).Perm
+	loose := assertSizeWithin(t, sizes, .2)
+	samplePerm = rand.New(rand.NewSource(1)).Perm
+	tight := assertSizeWithin(t, sizes, .001)
+	assert.True(t, tight > loose, "sampled %d partitions at .001 and %d at .2", tight, loose)
+}
+
+// newFakeSet returns a partitioned set of fake partitions
+func newFakeSet(partitions int) *set {
+	name := primitive.NewName("default", "test", "default", "test")
+	s := &set{
+		partitions:  make([]Set, partitions),
+		partitioner: util.GetPartitionIndex,
+	}
+	for i := range s.partitions {
+		s.partitions[i] = NewFake(name)
+	}
+	return s
+}
+
+func TestSetLenExact(t *testing.T) {
+	s := newFakeSet(2)
+
+	// Simulate an element that's transiently stored in both partitions
+	for _, partition := range s.partitions {
+		_, err := partition.Add(context.TODO(), "foo")
+		assert.NoError(t, err)
+	}
+	_, err := s.partitions[0].Add(context.TODO(), "bar")
+	assert.NoError(t, err)
+
+	// The default size sums the partitions' sizes and counts the duplicate element twice
+	size, err := s.Len(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, size)
+
+	size, err = s.Len(context.TODO(), Exact())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, size)
+
+	// An exact count fails if any partition is unavailable
+	s.partitions = append(s.partitions, &testPartition{})
+	_, err = s.Len(context.TODO(), Exact())
+	assert.Error(t, err)
+}
+
+func TestSetFakeAddConcurrent(t *testing.T) {
+	testAddConcurrent(t, newFakeSet(3))
+}
+
+func TestSetDiffUnion(t *testing.T) {
+	a, b := newFakeSet(3), newFakeSet(5)
+	var onlyA, onlyB []string
+	for i := 0; i < 3000; i++ {
+		value := fmt.Sprintf("%04d", i)
+		if i%3 != 1 {
+			_, err := a.Add(context.TODO(), value)
+			assert.NoError(t, err)
+		}
+		if i%3 != 2 {
+			_, err := b.Add(context.TODO(), value)
+			assert.NoError(t, err)
+		}
+		switch i % 3 {
+		case 1:
+			onlyB = append(onlyB, value)
+		case 2:
+			onlyA = append(onlyA, value)
+		}
+	}
+
+	diffA, diffB, err := Diff(context.TODO(), a, b)
+	assert.NoError(t, err)
+	assert.Equal(t, onlyA, diffA)
+	assert.Equal(t, onlyB, diffB)
+
+	diffA, diffB, err = Diff(context.TODO(), a, a)
+	assert.NoError(t, err)
+	assert.Empty(t, diffA)
+	assert.Empty(t, diffB)
+
+	dst := newFakeSet(4)
+	assert.NoError(t, Union(context.TODO(), dst, a, b))
+	size, err := dst.Len(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 3000, size)
+	diffA, diffB, err = Diff(context.TODO(), dst, newFakeSet(1))
+	assert.NoError(t, err)
+	assert.Len(t, diffA, 3000)
+	assert.Empty(t, diffB)
+
+	// Failing to read either set fails the diff
+	_, _, err = Diff(context.TODO(), a, &testPartition{})
+	assert.Error(t, err)
+	assert.Error(t, Union(context.TODO(), dst, a, &testPartition{}))
+
+	// A partially failed scan is drained so the set's barrier is released
+	s := &set{
+		partitions: []Set{
+			&testPartition{available: true, elements: []string{"foo", "bar"}},
+			&testPartition{},
+		},
+	}
+	_, _, err = Diff(context.TODO(), s, a)
+	assert.Error(t, err)
+	_, _, err = Diff(context.TODO(), a, s)
+	assert.Error(t, err)
+	cleared := make(chan error)
+	go func() {
+		cleared <- s.Clear(context.TODO(), WithBarrier())
+	}()
+	select {
+	case err := <-cleared:
+		assert.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("failed scan was not drained")
+	}
+}
+
+// deletedPartition is a Set partition that has already been deleted
+type deletedPartition struct {
+	Set
+	wrap bool
+}
+
+func (p *deletedPartition) Delete() error {
+	if p.wrap {
+		return fmt.Errorf("partition 1: %w", ErrNotFound)
+	}
+	return ErrNotFound
+}
+
+func TestSetDelete(t *testing.T) {
+	// Partitions that do not exist are ignored as long as some partition was deleted
+	s := newFakeSet(2)
+	s.partitions = append(s.partitions, &deletedPartition{})
+	assert.NoError(t, s.Delete())
+
+	s = &set{
+		partitions: []Set{&deletedPartition{}, &deletedPartition{}},
+	}
+	assert.Equal(t, ErrNotFound, s.Delete())
+
+	// Wrapped errors are recognized
+	s = &set{
+		partitions: []Set{&deletedPartition{wrap: true}, &deletedPartition{wrap: true}},
+	}
+	assert.Equal(t, ErrNotFound, s.Delete())
+}
+
+// containsPartition is a Set partition that counts Contains queries
+// If blocked, queries do not complete until they're canceled. If wait is set, queries do not complete until
+// it's closed.
+type containsPartition struct {
+	Set
+	blocked  bool
+	queries  int32
+	started  chan struct{}
+	canceled chan struct{}
+	wait     <-chan struct{}
+}
+
+func (p *containsPartition) Contains(ctx context.Context, value string, opts ...ContainsOption) (bool, error) {
+	atomic.AddInt32(&p.queries, 1)
+	if p.blocked {
+		close(p.started)
+		<-ctx.Done()
+		close(p.canceled)
+		return false, ctx.Err()
+	}
+	if p.wait != nil {
+		<-p.wait
+	}
+	return p.Set.Contains(ctx, value)
+}
+
+func (p *containsPartition) ContainsAny(ctx context.Context, values ...string) (bool, error) {
+	return containsAny(ctx, p, values)
+}
+
+func TestSetContainsAny(t *testing.T) {
+	s := newFakeSet(3)
+	for _, value := range []string{"foo", "bar"} {
+		_, err := s.Add(context.TODO(), value)
+		assert.NoError(t, err)
+	}
+
+	contains, err := s.ContainsAny(context.TODO())
+	assert.NoError(t, err)
+	assert.False(t, contains)
+	contains, err = s.ContainsAny(context.TODO(), "baz", "qux", "quux")
+	assert.NoError(t, err)
+	assert.False(t, contains)
+	contains, err = s.ContainsAny(context.TODO(), "baz", "bar")
+	assert.NoError(t, err)
+	assert.True(t, contains)
+
+	// Once a value is found, the queries still running on other partitions are canceled
+	name := primitive.NewName("default", "test", "default", "test")
+	blocked := &containsPartition{Set: NewFake(name), blocked: true, started: make(chan struct{}), canceled: make(chan struct{})}
+	found := &containsPartition{Set: NewFake(name), wait: blocked.started}
+	_, err = found.Add(context.TODO(), "foo")
+	assert.NoError(t, err)
+	s = &set{
+		partitions: []Set{found, blocked},
+		partitioner: func(key string, n int) (int, error) {
+			if key == "foo" {
+				return 0, nil
+			}
+			return 1, nil
+		},
+	}
+	contains, err = s.ContainsAny(context.TODO(), "bar", "foo", "baz")
+	assert.NoError(t, err)
+	assert.True(t, contains)
+	select {
+	case <-blocked.canceled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("query was not canceled")
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&found.queries))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&blocked.queries))
+
+	// A value found in one partition answers the query even if another partition is unavailable
+	s.partitions[1] = &testPartition{}
+	contains, err = s.ContainsAny(context.TODO(), "bar", "foo")
+	assert.NoError(t, err)
+	assert.True(t, contains)
+	_, err = s.ContainsAny(context.TODO(), "bar")
+	assert.Error(t, err)
+}
+
+func TestSetElementsSorted(t *testing.T) {
+	release := make(chan struct{})
+	s := &set{
+		partitions: []Set{
+			&testPartition{available: true, elements: []string{"foo", "bar"}, release: release},
+			&testPartition{available: true, elements: []string{"baz", "qux"}, release: release},
+		},
+	}
+
+	// By default elements are streamed before the partitions are done listing them
+	ch := make(chan string)
+	err := s.Elements(context.TODO(), ch)
+	assert.NoError(t, err)
+	elements := []string{<-ch, <-ch, <-ch, <-ch}
+	assert.ElementsMatch(t, []string{"foo", "bar", "baz", "qux"}, elements)
+	close(release)
+	_, ok := <-ch
+	assert.False(t, ok)
+
+	release = make(chan struct{})
+	for _, partition := range s.partitions {
+		partition.(*testPartition).release = release
+	}
+
+	// Sorted elements are only delivered once all partitions are done listing them
+	ch = make(chan string)
+	err = s.Elements(context.TODO(), ch, WithSorted())
+	assert.NoError(t, err)
+	select {
+	case element := <-ch:
+		t.Errorf("unexpected element %s", element)
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(release)
+
+	elements = make([]string, 0)
+	for element := range ch {
+		elements = append(elements, element)
+	}
+	assert.Equal(t, []string{"bar", "baz", "foo", "qux"}, elements)
+}
+
+// memoryPartition is an in-memory Set partition whose Clear blocks until released
+type memoryPartition struct {
+	Set
+	values   map[string]bool
+	started  chan struct{}
+	release  chan struct{}
+	watchers []*util.EventBuffer
+	mu       sync.Mutex
+}
+
+func (p *memoryPartition) Add(ctx context.Context, value string) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	added := !p.values[value]
+	p.values[value] = true
+	if added {
+		p.publish(&Event{Type: EventAdded, Value: value})
+	}
+	return added, nil
+}
+
+func (p *memoryPartition) Remove(ctx context.Context, value string) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	removed := p.values[value]
+	delete(p.values, value)
+	if removed {
+		p.publish(&Event{Type: EventRemoved, Value: value})
+	}
+	return removed, nil
+}
+
+func (p *memoryPartition) Watch(ctx context.Context, ch chan<- *Event, opts ...WatchOption) error {
+	buffer := util.NewEventBuffer(1000, util.OverflowBlock, func(event interface{}) {
+		ch <- event.(*Event)
+	}, nil)
+	p.mu.Lock()
+	p.watchers = append(p.watchers, buffer)
+	p.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		p.mu.Lock()
+		for i, watcher := range p.watchers {
+			if watcher == buffer {
+				p.watchers = append(p.watchers[:i], p.watchers[i+1:]...)
+				break
+			}
+		}
+		p.mu.Unlock()
+		buffer.Close()
+		close(ch)
+	}()
+	return nil
+}
+
+// publish publishes an event to the partition's watchers
+func (p *memoryPartition) publish(event *Event) {
+	for _, watcher := range p.watchers {
+		watcher.Push(event)
+	}
+}
+
+func (p *memoryPartition) Len(ctx context.Context, opts ...LenOption) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.values), nil
+}
+
+func (p *memoryPartition) Clear(ctx context.Context, opts ...ClearOption) error {
+	if p.release != nil {
+		close(p.started)
+		<-p.release
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.values = make(map[string]bool)
+	return nil
+}
+
+// newMemorySet returns a set with two values, one of which is cleared only once the returned channel is closed
+// The set's Clear has started once the returned started channel is closed.
+func newMemorySet() (*set, chan struct{}, chan struct{}) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	return &set{
+		partitions: []Set{
+			&memoryPartition{values: map[string]bool{"foo": true}},
+			&memoryPartition{values: map[string]bool{"bar": true}, started: started, release: release},
+		},
+		partitioner: util.GetPartitionIndex,
+	}, started, release
+}
+
+func TestSetClearBarrier(t *testing.T) {
+	// Without a barrier, a concurrent scan observes the partially cleared set
+	s, started, release := newMemorySet()
+	cleared := make(chan error)
+	go func() {
+		cleared <- s.Clear(context.TODO())
+	}()
+
+	<-started
+	size, err := s.Len(context.TODO())
+	for i := 0; err == nil && size != 1 && i < 100; i++ {
+		time.Sleep(10 * time.Millisecond)
+		size, err = s.Len(context.TODO())
+	}
+	assert.NoError(t, err)
+	assert.Equal(t, 1, size)
+	close(release)
+	assert.NoError(t, <-cleared)
+
+	// With a barrier, concurrent operations block until all partitions have been cleared
+	s, started, release = newMemorySet()
+	go func() {
+		cleared <- s.Clear(context.TODO(), WithBarrier())
+	}()
+	<-started
+
+	added := make(chan bool)
+	go func() {
+		ok, err := s.Add(context.TODO(), "baz")
+		assert.NoError(t, err)
+		added <- ok
+	}()
+
+	sizes := make(chan int)
+	go func() {
+		size, err := s.Len(context.TODO())
+		assert.NoError(t, err)
+		sizes <- size
+	}()
+
+	select {
+	case <-added:
+		t.Error("add was applied during the clear")
+	case size := <-sizes:
+		t.Errorf("scan observed a partially cleared set of size %d", size)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	assert.NoError(t, <-cleared)
+	assert.True(t, <-added)
+	size = <-sizes
+	assert.True(t, size == 0 || size == 1)
+
+	size, err = s.Len(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, size)
+}
+
+func TestSetElementsClearBarrier(t *testing.T) {
+	release := make(chan struct{})
+	s := &set{
+		partitions: []Set{
+			&testPartition{available: true, size: 2, elements: []string{"foo", "bar"}, release: release},
+			&testPartition{available: true, size: 2, elements: []string{"baz", "qux"}, release: release},
+		},
+	}
+
+	ch := make(chan string)
+	err := s.Elements(context.TODO(), ch)
+	assert.NoError(t, err)
+	elements := []string{<-ch}
+
+	// Start a clear that waits for the scan to complete
+	cleared := make(chan error)
+	go func() {
+		cleared <- s.Clear(context.TODO(), WithBarrier())
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// Call the set while iterating and while the clear is pending
+	sizes := make(chan int)
+	go func() {
+		size, err := s.Len(context.TODO())
+		assert.NoError(t, err)
+		sizes <- size
+	}()
+	close(release)
+
+	select {
+	case size := <-sizes:
+		assert.Equal(t, 4, size)
+	case <-time.After(5 * time.Second):
+		t.Fatal("set operation deadlocked with a pending clear")
+	}
+	assert.NoError(t, <-cleared)
+
+	for element := range ch {
+		elements = append(elements, element)
+	}
+	assert.ElementsMatch(t, []string{"foo", "bar", "baz", "qux"}, elements)
+}
+
+// eventPartition is a Set partition whose Watch publishes a burst of events
+type eventPartition struct {
+	Set
+	events int
+	sent   chan struct{}
+	err    error
+}
+
+func (p *eventPartition) Watch(ctx context.Context, ch chan<- *Event, opts ...WatchOption) error {
+	go func() {
+		for i := 0; i < p.events; i++ {
+			ch <- &Event{Type: EventAdded, Value: fmt.Sprintf("%d", i)}
+		}
+		close(p.sent)
+		if p.err != nil {
+			util.StreamErrorFromContext(ctx).Record(p.err)
+		}
+		close(ch)
+	}()
+	return nil
+}
+
+func TestSetWatchBuffer(t *testing.T) {
+	for _, policy := range []util.OverflowPolicy{util.OverflowDropOldest, util.OverflowDropNewest} {
+		partition := &eventPartition{events: 10, sent: make(chan struct{})}
+		s := &set{
+			partitions: []Set{partition},
+		}
+
+		var dropped uint64
+		ch := make(chan *Event)
+		err := s.Watch(context.TODO(), ch, WithBuffer(2, policy), WithDropCounter(&dropped))
+		assert.NoError(t, err)
+
+		// The partition is not blocked by the stalled consumer
+		select {
+		case <-partition.sent:
+		case <-time.After(5 * time.Second):
+			t.Fatal("partition was blocked by the consumer")
+		}
+
+		delivered := 0
+		for range ch {
+			delivered++
+		}
+		assert.Equal(t, uint64(10), uint64(delivered)+atomic.LoadUint64(&dropped))
+		assert.NotEqual(t, uint64(0), atomic.LoadUint64(&dropped))
+	}
+
+	// Blocking buffers deliver every event
+	partition := &eventPartition{events: 10, sent: make(chan struct{})}
+	s := &set{
+		partitions: []Set{partition},
+	}
+	ch := make(chan *Event)
+	err := s.Watch(context.TODO(), ch, WithBuffer(2, util.OverflowBlock))
+	assert.NoError(t, err)
+	delivered := 0
+	for range ch {
+		delivered++
+	}
+	assert.Equal(t, 10, delivered)
+}
+
+func TestSetWatchErrors(t *testing.T) {
+	name := primitive.NewName("default", "test", "default", "test")
+	failed := &eventPartition{Set: NewFake(name), events: 1, sent: make(chan struct{})}
+	s := &set{
+		partitions:  []Set{NewFake(name), failed},
+		partitioner: util.GetPartitionIndex,
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	ch := make(chan *Event)
+	errCh := make(chan *WatchError, 1)
+	err := s.Watch(ctx, ch, WithWatchErrors(errCh))
+	assert.NoError(t, err)
+
+	// The failed partition's events are delivered before the failure is reported
+	event := <-ch
+	assert.Equal(t, "0", event.Value)
+	watchErr := <-errCh
+	assert.Equal(t, 1, watchErr.Partition)
+	assert.Equal(t, ErrWatchClosed, watchErr.Err)
+	assert.True(t, watchErr.Recoverable)
+	assert.EqualError(t, watchErr, "partition 1: partition watch stream closed")
+
+	// The remaining partitions continue to deliver events
+	_, err = s.partitions[0].Add(context.TODO(), "foo")
+	assert.NoError(t, err)
+	event = <-ch
+	assert.Equal(t, EventAdded, event.Type)
+	assert.Equal(t, "foo", event.Value)
+
+	// Canceling the watch is not reported as a failure
+	cancel()
+	for range ch {
+	}
+	assert.Len(t, errCh, 0)
+
+	// The error that closed the stream is reported as the cause
+	failed = &eventPartition{Set: NewFake(name), sent: make(chan struct{}), err: session.ErrReconnectLimitExceeded}
+	s.partitions[1] = failed
+	ctx, cancel = context.WithCancel(context.TODO())
+	defer cancel()
+	ch = make(chan *Event)
+	err = s.Watch(ctx, ch, WithWatchErrors(errCh))
+	assert.NoError(t, err)
+	watchErr = <-errCh
+	assert.Equal(t, 1, watchErr.Partition)
+	assert.True(t, errors.Is(watchErr.Err, ErrWatchClosed))
+	assert.True(t, errors.Is(watchErr.Err, session.ErrReconnectLimitExceeded))
+	assert.EqualError(t, watchErr, "partition 1: partition watch stream closed: stream reconnect limit exceeded")
+}
+
+func TestSetWatchStats(t *testing.T) {
+	partition := &eventPartition{events: 10, sent: make(chan struct{})}
+	s := &set{
+		partitions: []Set{partition},
+	}
+
+	var dropped uint64
+	stats := &util.WatchStats{}
+	ch := make(chan *Event)
+	err := s.Watch(context.TODO(), ch, WithBuffer(2, util.OverflowDropNewest), WithDropCounter(&dropped), WithWatchStats(stats))
+	assert.NoError(t, err)
+	<-partition.sent
+
+	delivered := 0
+	for range ch {
+		delivered++
+	}
+	assert.Equal(t, uint64(delivered), stats.Delivered())
+	assert.Equal(t, atomic.LoadUint64(&dropped), stats.Dropped())
+	assert.Equal(t, uint64(10), stats.Delivered()+stats.Dropped())
+	assert.Equal(t, uint64(0), stats.LastVersion())
+}
+
+func TestSetWaitForSize(t *testing.T) {
+	s := &set{
+		partitions: []Set{
+			&memoryPartition{values: map[string]bool{}},
+			&memoryPartition{values: map[string]bool{}},
+		},
+		partitioner: util.GetPartitionIndex,
+	}
+
+	// Conditions that already hold return immediately
+	assert.NoError(t, s.WaitForSize(context.TODO(), 0, Eq))
+	assert.NoError(t, s.WaitForSize(context.TODO(), 0, Lte))
+
+	update := func(f func(ctx context.Context, value string) (bool, error), from, to int) {
+		go func() {
+			for i := from; i < to; i++ {
+				_, err := f(context.TODO(), fmt.Sprintf("value-%d", i))
+				assert.NoError(t, err)
+			}
+		}()
+	}
+
+	update(s.Add, 0, 10)
+	assert.NoError(t, s.WaitForSize(context.TODO(), 10, Gte))
+	size, err := s.Len(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 10, size)
+
+	update(s.Remove, 0, 5)
+	assert.NoError(t, s.WaitForSize(context.TODO(), 5, Lte))
+	size, err = s.Len(context.TODO())
+	assert.NoError(t, err)
+	assert.True(t, size <= 5)
+	assert.NoError(t, s.WaitForSize(context.TODO(), 5, Eq))
+
+	update(s.Add, 10, 13)
+	assert.NoError(t, s.WaitForSize(context.TODO(), 8, Eq))
+	size, err = s.Len(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 8, size)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, s.WaitForSize(ctx, 100, Gte))
+}
+
+// silentPartition is an in-memory Set partition whose watches never deliver events
+type silentPartition struct {
+	*memoryPartition
+}
+
+func (p *silentPartition) Watch(ctx context.Context, ch chan<- *Event, opts ...WatchOption) error {
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+	return nil
+}
+
+func TestSetWaitForSizeDrift(t *testing.T) {
+	interval := sizePollInterval
+	sizePollInterval = 10 * time.Millisecond
+	defer func() {
+		sizePollInterval = interval
+	}()
+
+	// The size is noticed even though the events for the changes are never received
+	p := &silentPartition{&memoryPartition{values: map[string]bool{"foo": true, "bar": true}}}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_, err := p.Remove(context.TODO(), "foo")
+		assert.NoError(t, err)
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, waitForSize(ctx, p, 1, Eq))
+}
+
+func TestSetWatchFilter(t *testing.T) {
+	s := &set{
+		partitions: []Set{
+			&memoryPartition{values: map[string]bool{}},
+			&memoryPartition{values: map[string]bool{}},
+		},
+		partitioner: util.GetPartitionIndex,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan *Event)
+	err := s.Watch(ctx, ch, WithFilter(EventRemoved))
+	assert.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		_, err = s.Add(context.TODO(), fmt.Sprintf("value-%d", i))
+		assert.NoError(t, err)
+	}
+	for i := 0; i < 3; i++ {
+		_, err = s.Remove(context.TODO(), fmt.Sprintf("value-%d", i))
+		assert.NoError(t, err)
+	}
+
+	removed := []string{}
+	for i := 0; i < 3; i++ {
+		event := <-ch
+		assert.Equal(t, EventRemoved, event.Type)
+		removed = append(removed, event.Value)
+	}
+	assert.ElementsMatch(t, []string{"value-0", "value-1", "value-2"}, removed)
+
+	cancel()
+	for event := range ch {
+		t.Errorf("unexpected %s event", event.Type)
+	}
+}
+
+func TestSetOperationNames(t *testing.T) {
+	conns, partitions := test.StartTestPartitions(1)
+
+	// Operations are reported to tracers and metrics by their primitive operation names
+	recorder := &test.OperationRecorder{}
+	name := primitive.NewName("default", "test", "default", "test")
+	set, err := New(context.TODO(), name, conns, append(recorder.Options(), session.WithTimeout(5*time.Second))...)
+	assert.NoError(t, err)
+
+	_, err = set.Add(context.TODO(), "foo")
+	assert.NoError(t, err)
+	_, err = set.Contains(context.TODO(), "foo")
+	assert.NoError(t, err)
+	_, err = set.Len(context.TODO())
+	assert.NoError(t, err)
+
+	for _, op := range []string{"set.Add", "set.Contains", "set.Len"} {
+		assert.Contains(t, recorder.Spans(), op)
+		assert.Contains(t, recorder.Observed(), op)
+	}
+
+	err = set.Close()
+	assert.NoError(t, err)
+	test.StopTestPartitions(partitions)
+}
@@ -0,0 +1,199 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package set
+
+import (
+	"context"
+	"errors"
+	"github.com/atomix/go-client/pkg/client/util"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// minSampleSize is the minimum number of partitions sampled to estimate the set size
+// Sets with no more partitions than the minimum sample are always sized exactly.
+const minSampleSize = 3
+
+// sampleFraction is the fraction of partitions sampled in the first round of a size estimate
+const sampleFraction = .25
+
+// confidenceZ is the z-score of the confidence level of size estimates (~99%)
+const confidenceZ = 2.576
+
+// samplePerm returns the order in which partitions are sampled
+var samplePerm = rand.Perm
+
+// SizeWithin estimates the size of the set by sampling partitions without replacement
+// Partitions are sampled in a random order, starting with sampleFraction of the partitions (at least
+// minSampleSize) and doubling the sample until the estimate converges. The total is estimated as the sample mean
+// multiplied by the number of partitions, and its margin of error is computed from the sample variance with a finite population correction at a ~99%
+// confidence level. Sampling stops once both the margin of error and the change in the estimate since the
+// previous round are within the requested tolerance. Skewed partition sizes produce a large sample variance,
+// causing more partitions to be sampled, up to the exact size once every partition has been queried.
+// Because the margin of error is computed from the sample itself, a sample that misses a few very large
+// partitions can underestimate the size, so the confidence level is lower for heavily skewed sets and for small
+// samples, where the sample mean is only roughly normally distributed. Values are distributed among partitions by
+// hash, so in practice partition sizes are close to uniform.
+func (s *set) SizeWithin(ctx context.Context, tolerance float64) (int, error) {
+	n := len(s.partitions)
+	if tolerance <= 0 || n <= minSampleSize {
+		return s.Len(ctx)
+	}
+
+	s.barrier.RLock()
+	defer s.barrier.RUnlock()
+
+	order := samplePerm(n)
+	sizes := make([]float64, 0, n)
+	previous := math.NaN()
+	k := int(math.Ceil(sampleFraction * float64(n)))
+	if k < minSampleSize {
+		k = minSampleSize
+	}
+	for {
+		offset := len(sizes)
+		results, err := util.ExecuteAsync(k-offset, func(i int) (interface{}, error) {
+			return s.partitions[order[offset+i]].Len(ctx)
+		})
+		if err != nil {
+			return 0, err
+		}
+		for _, result := range results {
+			sizes = append(sizes, float64(result.(int)))
+		}
+
+		estimate, margin := estimateSize(sizes, n)
+		if len(sizes) == n || (margin <= tolerance*estimate && math.Abs(estimate-previous) <= tolerance*estimate) {
+			return int(math.Round(estimate)), nil
+		}
+		previous = estimate
+
+		k *= 2
+		if k > n {
+			k = n
+		}
+	}
+}
+
+// estimateSize estimates the total size of n partitions from the given sample of partition sizes
+// The estimate is returned with its margin of error.
+func estimateSize(sizes []float64, n int) (float64, float64) {
+	k := float64(len(sizes))
+	sum := 0.0
+	for _, size := range sizes {
+		sum += size
+	}
+	mean := sum / k
+	if len(sizes) == n {
+		return sum, 0
+	}
+
+	variance := 0.0
+	for _, size := range sizes {
+		variance += (size - mean) * (size - mean)
+	}
+	variance /= k - 1
+
+	N := float64(n)
+	fpc := (N - k) / (N - 1)
+	stderr := N * math.Sqrt(variance/k*fpc)
+	return N * mean, confidenceZ * stderr
+}
+
+// Comparison is a comparison of the set size to a target size
+type Comparison int
+
+const (
+	// Eq is satisfied when the set size is equal to the target
+	Eq Comparison = iota
+
+	// Gte is satisfied when the set size is greater than or equal to the target
+	Gte
+
+	// Lte is satisfied when the set size is less than or equal to the target
+	Lte
+)
+
+// holds returns whether the given size compares to the target according to the comparison
+func (c Comparison) holds(size int, target int) bool {
+	switch c {
+	case Gte:
+		return size >= target
+	case Lte:
+		return size <= target
+	default:
+		return size == target
+	}
+}
+
+func (s *set) WaitForSize(ctx context.Context, target int, cmp Comparison) error {
+	return waitForSize(ctx, s, target, cmp)
+}
+
+func (s *setPartition) WaitForSize(ctx context.Context, target int, cmp Comparison) error {
+	return waitForSize(ctx, s, target, cmp)
+}
+
+// sizePollInterval is the interval at which waitForSize reads the size while no events are received
+var sizePollInterval = time.Second
+
+// waitForSize waits for the size of the given set to satisfy the comparison
+// The set is watched so that changes are noticed promptly, but events are only used as a signal that the size
+// may have changed: the size is read again after every event rather than tracked by counting events, which would
+// drift if events are dropped or filtered. The size is also read every sizePollInterval so the condition is
+// noticed even if the events for the changes that satisfy it are never received.
+func waitForSize(ctx context.Context, s Set, target int, cmp Comparison) error {
+	size, err := s.Len(ctx)
+	if err != nil {
+		return err
+	} else if cmp.holds(size, target) {
+		return nil
+	}
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	ch := make(chan *Event)
+	if err := s.Watch(watchCtx, ch); err != nil {
+		cancel()
+		return err
+	}
+	defer func() {
+		cancel()
+		for range ch {
+		}
+	}()
+
+	ticker := time.NewTicker(sizePollInterval)
+	defer ticker.Stop()
+	for {
+		if size, err = s.Len(ctx); err != nil {
+			return err
+		} else if cmp.holds(size, target) {
+			return nil
+		}
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				return errors.New("set watch closed")
+			}
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	controllerapi "github.com/atomix/api/proto/atomix/controller"
+	primitiveapi "github.com/atomix/api/proto/atomix/primitive"
+	"github.com/atomix/go-client/pkg/client/util"
+	"github.com/atomix/go-client/pkg/client/util/net"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// PartitionState is the provisioning state of a partition
+type PartitionState string
+
+const (
+	// PartitionPending indicates the partition has not yet been provisioned or is not yet reachable
+	PartitionPending PartitionState = "pending"
+
+	// PartitionReady indicates the partition is serving requests
+	PartitionReady PartitionState = "ready"
+
+	// PartitionFailed indicates the partition failed to serve requests
+	PartitionFailed PartitionState = "failed"
+)
+
+// PartitionStatus is the provisioning status of a partition
+type PartitionStatus struct {
+	// ID is the partition identifier
+	ID int
+
+	// Address is the address of the partition, or empty if the partition has not been assigned an endpoint
+	Address net.Address
+
+	// State is the provisioning state of the partition
+	State PartitionState
+}
+
+// GroupStatus is the provisioning status of a partition group
+type GroupStatus struct {
+	Namespace  string
+	Name       string
+	Partitions []PartitionStatus
+}
+
+// Ready returns a bool indicating whether all partitions in the group are ready
+func (s *GroupStatus) Ready() bool {
+	for _, partition := range s.Partitions {
+		if partition.State != PartitionReady {
+			return false
+		}
+	}
+	return true
+}
+
+// ProbeTimeout is the maximum time GetGroupStatus waits for a partition to respond to a probe
+// A partition that doesn't respond within the timeout is pending. The timeout is derived from the context passed
+// to GetGroupStatus, so a shorter deadline or cancellation of that context takes precedence.
+const ProbeTimeout = 5 * time.Second
+
+// GetGroupStatus returns the provisioning status of each partition in the partition group
+// Partitions that have not been assigned an endpoint by the controller are pending. Partitions with an endpoint
+// are probed to determine whether they're ready. If the context is canceled or its deadline is exceeded before
+// the partitions have been probed, the context's error is returned.
+func (c *Client) GetGroupStatus(ctx context.Context, name string) (*GroupStatus, error) {
+	groupProto, err := c.getGroup(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return newGroupStatus(ctx, groupProto, probePartition)
+}
+
+// newGroupStatus computes the status of the given partition group using the given function to probe partitions
+func newGroupStatus(ctx context.Context, groupProto *controllerapi.PartitionGroup, probe func(ctx context.Context, address net.Address) error) (*GroupStatus, error) {
+	addresses := make(map[int]net.Address)
+	for _, partitionProto := range groupProto.Partitions {
+		if len(partitionProto.Endpoints) > 0 {
+			ep := partitionProto.Endpoints[0]
+			addresses[int(partitionProto.PartitionID)] = net.Address(fmt.Sprintf("%s:%d", ep.Host, ep.Port))
+		}
+	}
+
+	partitions := make([]PartitionStatus, groupProto.Spec.Partitions)
+	err := util.IterAsync(len(partitions), func(i int) error {
+		id := i + 1
+		address, ok := addresses[id]
+		partitions[i] = PartitionStatus{
+			ID:      id,
+			Address: address,
+			State:   PartitionPending,
+		}
+		if !ok {
+			return nil
+		}
+
+		err := probe(ctx, address)
+		if err == nil {
+			partitions[i].State = PartitionReady
+		} else if code := status.Code(err); code != codes.Unavailable && code != codes.DeadlineExceeded {
+			partitions[i].State = PartitionFailed
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return &GroupStatus{
+		Namespace:  groupProto.ID.Namespace,
+		Name:       groupProto.ID.Name,
+		Partitions: partitions,
+	}, nil
+}
+
+// probePartition sends a request to the partition at the given address to determine whether it's ready
+// The probe is bounded by ProbeTimeout and by the given context.
+func probePartition(ctx context.Context, address net.Address) error {
+	conn, err := net.Connect(address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	client := primitiveapi.NewPrimitiveServiceClient(conn)
+	ctx, cancel := context.WithTimeout(ctx, ProbeTimeout)
+	defer cancel()
+	_, err = client.GetPrimitives(ctx, &primitiveapi.GetPrimitivesRequest{})
+	return err
+}
@@ -0,0 +1,69 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"github.com/atomix/go-client/pkg/client/session"
+	"sync"
+	"time"
+)
+
+// OperationRecorder is a session Tracer and Metrics that records the names of traced and observed operations
+// It's used to verify the operation names primitives report to tracers and metrics.
+type OperationRecorder struct {
+	mu       sync.Mutex
+	spans    []string
+	observed []string
+}
+
+// Start records the name of the operation for which a span is started
+func (r *OperationRecorder) Start(ctx context.Context, op session.Operation) (context.Context, session.Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, op.Name)
+	return ctx, recorderSpan{}
+}
+
+// Observe records the name of the observed operation
+func (r *OperationRecorder) Observe(op session.Operation, duration time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.observed = append(r.observed, op.Name)
+}
+
+// Spans returns the names of the operations for which spans were started
+func (r *OperationRecorder) Spans() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string{}, r.spans...)
+}
+
+// Observed returns the names of the operations observed by the metrics
+func (r *OperationRecorder) Observed() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string{}, r.observed...)
+}
+
+// Options returns the session options to trace and observe operations with the recorder
+func (r *OperationRecorder) Options() []session.Option {
+	return []session.Option{session.WithTracer(r), session.WithMetrics(r)}
+}
+
+// recorderSpan is the span returned by the OperationRecorder
+type recorderSpan struct{}
+
+func (recorderSpan) End(op session.Operation, err error) {}
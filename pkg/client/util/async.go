@@ -15,10 +15,17 @@
 package util
 
 import (
-	"sort"
+	"context"
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"sync"
 )
 
+// ErrAllPartitionsUnavailable is returned by partitioned operations when every partition failed because it
+// was unavailable, including partitions that were still unavailable when the operation's context ended
+var ErrAllPartitionsUnavailable = errors.New("all partitions are unavailable")
+
 // IterAsync executes the given function f up to n times concurrently.
 // Each call is done in a separate goroutine. On each iteration, the function f
 // will be called with a unique sequential index i such that the index can be
@@ -51,14 +58,48 @@ func IterAsync(n int, f func(i int) error) error {
 	return nil
 }
 
-// ExecuteAsync executes the given function f up to n times concurrently, populating
-// the given results slice with the results of each function call.
-// Each call is done in a separate goroutine. On each iteration, the function f
-// will be called with a unique sequential index i such that the index can be
-// used to reference an element in an array or slice. If an error is returned
-// by the function f for any index, an error will be returned. Otherwise,
-// a nil result will be returned once all function calls have completed.
-func ExecuteAsync(n int, f func(i int) (interface{}, error)) ([]interface{}, error) {
+// IterPartitionsAsync executes the given function f once for each of n partitions concurrently.
+// Unlike IterAsync, IterPartitionsAsync waits for all function calls to complete before returning.
+// If every one of multiple partitions fails with an Unavailable error, ErrAllPartitionsUnavailable will
+// be returned. If any other error occurs, the first error will be returned. Otherwise, a nil result
+// will be returned once all function calls have completed.
+func IterPartitionsAsync(n int, f func(i int) error) error {
+	_, err := ExecutePartitionsAsync(n, func(i int) (interface{}, error) {
+		return nil, f(i)
+	})
+	return err
+}
+
+// IterLimitedAsync executes the given function f n times with at most limit calls running concurrently.
+// On each iteration, the function f will be called with a unique sequential index i such that the index can be
+// used to reference an element in an array or slice. IterLimitedAsync returns once all function calls have
+// completed. If limit is not positive, all n calls are run concurrently.
+func IterLimitedAsync(n int, limit int, f func(i int)) {
+	if limit <= 0 || limit > n {
+		limit = n
+	}
+
+	wg := sync.WaitGroup{}
+	slots := make(chan struct{}, limit)
+
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		slots <- struct{}{}
+		go func(j int) {
+			f(j)
+			<-slots
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+}
+
+// ExecutePartitionsAsync executes the given function f once for each of n partitions concurrently,
+// returning the results of each function call.
+// Unlike ExecuteAsync, ExecutePartitionsAsync waits for all function calls to complete before returning.
+// If every one of multiple partitions fails with an Unavailable error, ErrAllPartitionsUnavailable will
+// be returned. If any other error occurs, the first error will be returned so callers can inspect the cause.
+func ExecutePartitionsAsync(n int, f func(i int) (interface{}, error)) ([]interface{}, error) {
 	wg := sync.WaitGroup{}
 	asyncErrors := make(chan error, n)
 	asyncResults := make(chan interface{}, n)
@@ -76,14 +117,19 @@ func ExecuteAsync(n int, f func(i int) (interface{}, error)) ([]interface{}, err
 		}(i)
 	}
 
-	go func() {
-		wg.Wait()
-		close(asyncErrors)
-		close(asyncResults)
-	}()
-
-	for err := range asyncErrors {
-		return nil, err
+	wg.Wait()
+	close(asyncErrors)
+	close(asyncResults)
+
+	if len(asyncErrors) > 0 {
+		errs := make([]error, 0, len(asyncErrors))
+		for err := range asyncErrors {
+			errs = append(errs, err)
+		}
+		if n > 1 && len(errs) == n && allUnavailable(errs) {
+			return nil, ErrAllPartitionsUnavailable
+		}
+		return nil, errs[0]
 	}
 
 	results := make([]interface{}, 0, n)
@@ -93,17 +139,27 @@ func ExecuteAsync(n int, f func(i int) (interface{}, error)) ([]interface{}, err
 	return results, nil
 }
 
-// ExecuteOrderedAsync executes the given function f up to n times concurrently, populating
+// allUnavailable returns whether every one of the given errors is an Unavailable error
+func allUnavailable(errs []error) bool {
+	for _, err := range errs {
+		if status.Code(err) != codes.Unavailable {
+			return false
+		}
+	}
+	return true
+}
+
+// ExecuteAsync executes the given function f up to n times concurrently, populating
 // the given results slice with the results of each function call.
 // Each call is done in a separate goroutine. On each iteration, the function f
 // will be called with a unique sequential index i such that the index can be
 // used to reference an element in an array or slice. If an error is returned
 // by the function f for any index, an error will be returned. Otherwise,
 // a nil result will be returned once all function calls have completed.
-func ExecuteOrderedAsync(n int, f func(i int) (interface{}, error)) ([]interface{}, error) {
+func ExecuteAsync(n int, f func(i int) (interface{}, error)) ([]interface{}, error) {
 	wg := sync.WaitGroup{}
 	asyncErrors := make(chan error, n)
-	asyncResults := make(chan *asyncResult, n)
+	asyncResults := make(chan interface{}, n)
 
 	wg.Add(n)
 	for i := 0; i < n; i++ {
@@ -112,10 +168,7 @@ func ExecuteOrderedAsync(n int, f func(i int) (interface{}, error)) ([]interface
 			if err != nil {
 				asyncErrors <- err
 			} else {
-				asyncResults <- &asyncResult{
-					i:      j,
-					result: result,
-				}
+				asyncResults <- result
 			}
 			wg.Done()
 		}(i)
@@ -131,24 +184,69 @@ func ExecuteOrderedAsync(n int, f func(i int) (interface{}, error)) ([]interface
 		return nil, err
 	}
 
-	sortedResults := make([]*asyncResult, 0, n)
+	results := make([]interface{}, 0, n)
 	for result := range asyncResults {
-		sortedResults = append(sortedResults, result)
+		results = append(results, result)
 	}
+	return results, nil
+}
 
-	sort.Slice(sortedResults, func(i, j int) bool {
-		return sortedResults[i].i < sortedResults[j].i
-	})
+// ExecuteOrderedAsync executes the given function f up to n times concurrently, populating
+// the given results slice with the results of each function call.
+// Each call is done in a separate goroutine. On each iteration, the function f
+// will be called with a unique sequential index i such that the index can be
+// used to reference an element in an array or slice. If an error is returned
+// by the function f for any index, an error will be returned. Otherwise,
+// a nil result will be returned once all function calls have completed.
+func ExecuteOrderedAsync(n int, f func(i int) (interface{}, error)) ([]interface{}, error) {
+	return ExecuteOrderedAsyncContext(context.Background(), n, func(ctx context.Context, i int) (interface{}, error) {
+		return f(i)
+	}, nil)
+}
 
+// ExecuteOrderedAsyncContext executes the given function f up to n times concurrently, returning the results
+// of each function call in order.
+// Each call is passed a context derived from the given context. If the function f returns an error for any
+// index, the context is canceled to abort the calls still in flight, and once all calls have returned, the
+// cleanup function is called with the result of each call that succeeded and the first error is returned.
+// This allows resources created by a partially failed operation to be released. The cleanup function may be nil.
+func ExecuteOrderedAsyncContext(ctx context.Context, n int, f func(ctx context.Context, i int) (interface{}, error), cleanup func(result interface{})) ([]interface{}, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	wg := sync.WaitGroup{}
 	results := make([]interface{}, n)
-	for i, result := range sortedResults {
-		results[i] = result.result
+	succeeded := make([]bool, n)
+	var failure error
+	var once sync.Once
+
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(j int) {
+			defer wg.Done()
+			result, err := f(ctx, j)
+			if err != nil {
+				once.Do(func() {
+					failure = err
+					cancel()
+				})
+				return
+			}
+			results[j] = result
+			succeeded[j] = true
+		}(i)
 	}
+	wg.Wait()
 
+	if failure != nil {
+		if cleanup != nil {
+			for i, result := range results {
+				if succeeded[i] {
+					cleanup(result)
+				}
+			}
+		}
+		return nil, failure
+	}
 	return results, nil
 }
-
-type asyncResult struct {
-	i      int
-	result interface{}
-}
@@ -15,8 +15,14 @@
 package util
 
 import (
+	"context"
+	"errors"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestRunAsync(t *testing.T) {
@@ -62,3 +68,138 @@ func TestExecuteOrderedAsync(t *testing.T) {
 	assert.Equal(t, "two", results[1].(string))
 	assert.Equal(t, "three", results[2].(string))
 }
+
+func TestExecuteOrderedAsyncContext(t *testing.T) {
+	cleaned := make(chan interface{}, 3)
+	cleanup := func(result interface{}) {
+		cleaned <- result
+	}
+
+	results, err := ExecuteOrderedAsyncContext(context.TODO(), 3, func(ctx context.Context, i int) (interface{}, error) {
+		return i, nil
+	}, cleanup)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{0, 1, 2}, results)
+	assert.Len(t, cleaned, 0)
+
+	// A failure cancels the calls in flight and cleans up the results of the calls that succeeded
+	canceled := make(chan bool, 1)
+	_, err = ExecuteOrderedAsyncContext(context.TODO(), 3, func(ctx context.Context, i int) (interface{}, error) {
+		switch i {
+		case 1:
+			return nil, errors.New("unavailable")
+		case 2:
+			<-ctx.Done()
+			canceled <- true
+			return nil, ctx.Err()
+		}
+		return i, nil
+	}, cleanup)
+	assert.EqualError(t, err, "unavailable")
+	assert.True(t, <-canceled)
+	assert.Len(t, cleaned, 1)
+	assert.Equal(t, 0, <-cleaned)
+}
+
+func TestIterPartitionsAsync(t *testing.T) {
+	err := IterPartitionsAsync(3, func(i int) error {
+		return nil
+	})
+	assert.NoError(t, err)
+
+	err = IterPartitionsAsync(3, func(i int) error {
+		if i == 1 {
+			return errors.New("unavailable")
+		}
+		return nil
+	})
+	assert.EqualError(t, err, "unavailable")
+
+	err = IterPartitionsAsync(3, func(i int) error {
+		return status.Error(codes.Unavailable, "unavailable")
+	})
+	assert.Equal(t, ErrAllPartitionsUnavailable, err)
+
+	// Errors other than Unavailable are returned as is
+	err = IterPartitionsAsync(3, func(i int) error {
+		return errors.New("invalid")
+	})
+	assert.EqualError(t, err, "invalid")
+
+	// A single unavailable partition returns its own error
+	err = IterPartitionsAsync(1, func(i int) error {
+		return status.Error(codes.Unavailable, "unavailable")
+	})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.NotEqual(t, ErrAllPartitionsUnavailable, err)
+}
+
+func TestExecutePartitionsAsync(t *testing.T) {
+	results, err := ExecutePartitionsAsync(3, func(i int) (interface{}, error) {
+		return i, nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+
+	_, err = ExecutePartitionsAsync(3, func(i int) (interface{}, error) {
+		if i == 0 {
+			return nil, errors.New("unavailable")
+		}
+		return i, nil
+	})
+	assert.EqualError(t, err, "unavailable")
+
+	_, err = ExecutePartitionsAsync(3, func(i int) (interface{}, error) {
+		return nil, status.Error(codes.Unavailable, "unavailable")
+	})
+	assert.Equal(t, ErrAllPartitionsUnavailable, err)
+
+	// Mixed failures return the cause rather than ErrAllPartitionsUnavailable
+	_, err = ExecutePartitionsAsync(3, func(i int) (interface{}, error) {
+		if i == 0 {
+			return nil, errors.New("invalid")
+		}
+		return nil, status.Error(codes.Unavailable, "unavailable")
+	})
+	assert.NotEqual(t, ErrAllPartitionsUnavailable, err)
+}
+
+func TestExecutePartitionsAsyncCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := ExecutePartitionsAsync(3, func(i int) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestIterLimitedAsync(t *testing.T) {
+	var mu sync.Mutex
+	running := 0
+	maxRunning := 0
+	called := make([]bool, 10)
+	IterLimitedAsync(len(called), 3, func(i int) {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+		called[i] = true
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+	})
+	assert.Equal(t, 3, maxRunning)
+	for i := range called {
+		assert.True(t, called[i])
+	}
+
+	IterLimitedAsync(0, 3, func(i int) {
+		t.Error("unexpected call")
+	})
+}
@@ -0,0 +1,122 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import "sync"
+
+// OverflowPolicy is the policy applied to events pushed to a full EventBuffer
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks the producer until the consumer frees space in the buffer
+	// No events are lost, but a slow consumer stalls the producer.
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDropOldest drops the oldest buffered event to make room for the new event
+	// The consumer always sees the most recent events, but may miss intermediate events.
+	OverflowDropOldest
+
+	// OverflowDropNewest drops the new event, keeping the events already buffered
+	// The consumer sees events in order up to the point it fell behind, but misses the most recent events.
+	OverflowDropNewest
+)
+
+// NewEventBuffer returns a new EventBuffer that delivers events to the given function
+// The dropped function is called for each event dropped by the buffer's policy and may be nil.
+func NewEventBuffer(size int, policy OverflowPolicy, deliver func(event interface{}), dropped func()) *EventBuffer {
+	buffer := &EventBuffer{
+		size:    size,
+		policy:  policy,
+		deliver: deliver,
+		dropped: dropped,
+		done:    make(chan struct{}),
+	}
+	buffer.cond = sync.NewCond(&buffer.mu)
+	go buffer.run()
+	return buffer
+}
+
+// EventBuffer decouples an event producer from a slow consumer
+// Events are pushed to the buffer by the producer and delivered in order by a separate goroutine. When the buffer
+// is full, pushed events are handled according to the buffer's OverflowPolicy.
+type EventBuffer struct {
+	size    int
+	policy  OverflowPolicy
+	deliver func(event interface{})
+	dropped func()
+	events  []interface{}
+	closed  bool
+	done    chan struct{}
+	mu      sync.Mutex
+	cond    *sync.Cond
+}
+
+// Push pushes an event to the buffer
+func (b *EventBuffer) Push(event interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.events) >= b.size {
+		switch b.policy {
+		case OverflowBlock:
+			for len(b.events) >= b.size {
+				b.cond.Wait()
+			}
+		case OverflowDropOldest:
+			b.events = b.events[1:]
+			b.drop()
+		case OverflowDropNewest:
+			b.drop()
+			return
+		}
+	}
+	b.events = append(b.events, event)
+	b.cond.Broadcast()
+}
+
+// drop records a dropped event
+func (b *EventBuffer) drop() {
+	if b.dropped != nil {
+		b.dropped()
+	}
+}
+
+// Close closes the buffer, returning once all buffered events have been delivered
+func (b *EventBuffer) Close() {
+	b.mu.Lock()
+	b.closed = true
+	b.cond.Broadcast()
+	b.mu.Unlock()
+	<-b.done
+}
+
+// run delivers buffered events until the buffer is closed and drained
+func (b *EventBuffer) run() {
+	defer close(b.done)
+	for {
+		b.mu.Lock()
+		for len(b.events) == 0 && !b.closed {
+			b.cond.Wait()
+		}
+		if len(b.events) == 0 {
+			b.mu.Unlock()
+			return
+		}
+		event := b.events[0]
+		b.events = b.events[1:]
+		b.cond.Broadcast()
+		b.mu.Unlock()
+		b.deliver(event)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// newTestBuffer returns a buffer whose consumer is stalled until the returned channel is closed
+func newTestBuffer(size int, policy OverflowPolicy) (*EventBuffer, chan<- struct{}, *[]interface{}, *int) {
+	release := make(chan struct{})
+	delivered := []interface{}{}
+	dropped := 0
+	buffer := NewEventBuffer(size, policy, func(event interface{}) {
+		<-release
+		delivered = append(delivered, event)
+	}, func() {
+		dropped++
+	})
+	return buffer, release, &delivered, &dropped
+}
+
+// awaitEmpty waits for the buffer's consumer to take all buffered events
+func awaitEmpty(buffer *EventBuffer) {
+	for {
+		buffer.mu.Lock()
+		n := len(buffer.events)
+		buffer.mu.Unlock()
+		if n == 0 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestEventBufferBlock(t *testing.T) {
+	buffer, release, delivered, dropped := newTestBuffer(2, OverflowBlock)
+
+	// One event is held by the stalled consumer and two are buffered
+	for i := 0; i < 3; i++ {
+		buffer.Push(i)
+	}
+
+	pushed := make(chan struct{})
+	go func() {
+		buffer.Push(3)
+		close(pushed)
+	}()
+	select {
+	case <-pushed:
+		t.Error("push to a full buffer did not block")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	<-pushed
+	buffer.Close()
+	assert.Equal(t, []interface{}{0, 1, 2, 3}, *delivered)
+	assert.Equal(t, 0, *dropped)
+}
+
+func TestEventBufferDropOldest(t *testing.T) {
+	buffer, release, delivered, dropped := newTestBuffer(2, OverflowDropOldest)
+	buffer.Push(0)
+	awaitEmpty(buffer)
+	for i := 1; i < 5; i++ {
+		buffer.Push(i)
+	}
+	close(release)
+	buffer.Close()
+	assert.Equal(t, []interface{}{0, 3, 4}, *delivered)
+	assert.Equal(t, 2, *dropped)
+}
+
+func TestEventBufferDropNewest(t *testing.T) {
+	buffer, release, delivered, dropped := newTestBuffer(2, OverflowDropNewest)
+	buffer.Push(0)
+	awaitEmpty(buffer)
+	for i := 1; i < 5; i++ {
+		buffer.Push(i)
+	}
+	close(release)
+	buffer.Close()
+	assert.Equal(t, []interface{}{0, 1, 2}, *delivered)
+	assert.Equal(t, 2, *dropped)
+}
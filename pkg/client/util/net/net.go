@@ -70,17 +70,25 @@ func (c *Conns) Connect() (*grpc.ClientConn, error) {
 	return conn, nil
 }
 
+// Leader returns the address of the leader to which the connection is made
+func (c *Conns) Leader() Address {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.leader
+}
+
 // Reconnect reconnects the client to the given leader if necessary
-func (c *Conns) Reconnect(leader Address) {
+// Returns a bool indicating whether the connection was changed to a new leader.
+func (c *Conns) Reconnect(leader Address) bool {
 	if leader == "" {
-		return
+		return false
 	}
 
 	c.mu.RLock()
 	connLeader := c.leader
 	c.mu.RUnlock()
 	if connLeader == leader {
-		return
+		return false
 	}
 
 	c.mu.Lock()
@@ -90,6 +98,7 @@ func (c *Conns) Reconnect(leader Address) {
 		c.conn.Close()
 		c.conn = nil
 	}
+	return true
 }
 
 // Close closes the connections
@@ -16,7 +16,12 @@ package util
 
 import "hash/fnv"
 
+// Partitioner returns the index of the partition for the given key
+type Partitioner func(key string, partitions int) (int, error)
+
 // GetPartitionIndex returns the index of the partition for the given key
+// Keys are mapped to partitions by the modulo of the key's hash. Changing the number of partitions remaps
+// nearly every key.
 func GetPartitionIndex(key string, partitions int) (int, error) {
 	h := fnv.New32a()
 	if _, err := h.Write([]byte(key)); err != nil {
@@ -24,3 +29,34 @@ func GetPartitionIndex(key string, partitions int) (int, error) {
 	}
 	return int(h.Sum32() % uint32(partitions)), nil
 }
+
+// GetRendezvousPartitionIndex returns the index of the partition for the given key using rendezvous hashing
+// The key is mapped to the partition with the highest weight computed from the key and the partition index.
+// Adding a partition to N partitions only remaps the ~1/(N+1) keys for which the new partition has the highest
+// weight. Rendezvous hashing places keys differently than GetPartitionIndex, so switching an existing partition
+// group between the two strategies will make previously written keys unreachable until they're migrated.
+func GetRendezvousPartitionIndex(key string, partitions int) (int, error) {
+	h := fnv.New64a()
+	if _, err := h.Write([]byte(key)); err != nil {
+		return 0, err
+	}
+	hash := h.Sum64()
+
+	index := 0
+	var max uint64
+	for i := 0; i < partitions; i++ {
+		weight := mix(hash ^ mix(uint64(i)))
+		if i == 0 || weight > max {
+			index = i
+			max = weight
+		}
+	}
+	return index, nil
+}
+
+// mix is the splitmix64 finalizer, used to scramble partition weights
+func mix(x uint64) uint64 {
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
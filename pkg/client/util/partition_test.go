@@ -0,0 +1,68 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// getChurn returns the fraction of keys remapped when growing from n to n+1 partitions, and the key counts per partition
+func getChurn(t *testing.T, partitioner Partitioner, keys int, n int) (float64, []int) {
+	remapped := 0
+	counts := make([]int, n+1)
+	for i := 0; i < keys; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		before, err := partitioner(key, n)
+		assert.NoError(t, err)
+		after, err := partitioner(key, n+1)
+		assert.NoError(t, err)
+		if before != after {
+			remapped++
+		}
+		counts[after]++
+	}
+	return float64(remapped) / float64(keys), counts
+}
+
+func TestPartitionChurn(t *testing.T) {
+	keys := 10000
+	n := 8
+
+	moduloChurn, _ := getChurn(t, GetPartitionIndex, keys, n)
+	rendezvousChurn, counts := getChurn(t, GetRendezvousPartitionIndex, keys, n)
+
+	// Modulo hashing remaps ~N/(N+1) of the keys whereas rendezvous hashing remaps ~1/(N+1)
+	assert.True(t, moduloChurn > .5, "modulo churn %f", moduloChurn)
+	assert.True(t, rendezvousChurn < .15, "rendezvous churn %f", rendezvousChurn)
+
+	// Keys are distributed evenly among partitions
+	for i, count := range counts {
+		assert.InDelta(t, keys/(n+1), count, float64(keys/(n+1))*.15, "partition %d", i)
+	}
+}
+
+func TestRendezvousPartitionIndex(t *testing.T) {
+	i, err := GetRendezvousPartitionIndex("foo", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, i)
+
+	i, err = GetRendezvousPartitionIndex("foo", 3)
+	assert.NoError(t, err)
+	j, err := GetRendezvousPartitionIndex("foo", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, i, j)
+}
@@ -0,0 +1,155 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WatchStats tracks the delivery of a watch's events
+// The stats are updated atomically by the watch as events are delivered and may be read from any goroutine while
+// the watch is running. The record methods are called by primitives and may be called on a nil WatchStats, in
+// which case nothing is recorded.
+type WatchStats struct {
+	delivered uint64
+	dropped   uint64
+	version   uint64
+	lastEvent int64
+}
+
+// Delivered returns the number of events delivered to the watch's channel
+func (s *WatchStats) Delivered() uint64 {
+	return atomic.LoadUint64(&s.delivered)
+}
+
+// Dropped returns the number of events dropped by the watch's buffer
+func (s *WatchStats) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// LastVersion returns the version of the last delivered event
+// The version is always 0 for primitives whose events are not versioned.
+func (s *WatchStats) LastVersion() uint64 {
+	return atomic.LoadUint64(&s.version)
+}
+
+// SinceLastEvent returns the time since the last event was delivered, or 0 if no event has been delivered
+func (s *WatchStats) SinceLastEvent() time.Duration {
+	lastEvent := atomic.LoadInt64(&s.lastEvent)
+	if lastEvent == 0 {
+		return 0
+	}
+	return time.Since(time.Unix(0, lastEvent))
+}
+
+// Fields returns the stats as structured logging fields
+func (s *WatchStats) Fields() []interface{} {
+	return []interface{}{
+		"delivered", s.Delivered(),
+		"dropped", s.Dropped(),
+		"lastVersion", s.LastVersion(),
+		"sinceLastEvent", s.SinceLastEvent(),
+	}
+}
+
+// RecordDelivered records the delivery of an event
+func (s *WatchStats) RecordDelivered() {
+	if s == nil {
+		return
+	}
+	atomic.AddUint64(&s.delivered, 1)
+	atomic.StoreInt64(&s.lastEvent, time.Now().UnixNano())
+}
+
+// RecordVersion records the version of a delivered event
+func (s *WatchStats) RecordVersion(version uint64) {
+	if s == nil {
+		return
+	}
+	atomic.StoreUint64(&s.version, version)
+}
+
+// DroppedFunc returns an EventBuffer dropped function that records dropped events before calling dropped
+// The dropped function may be nil.
+func (s *WatchStats) DroppedFunc(dropped func()) func() {
+	if s == nil {
+		return dropped
+	}
+	return func() {
+		atomic.AddUint64(&s.dropped, 1)
+		if dropped != nil {
+			dropped()
+		}
+	}
+}
+
+type watchStatsKey struct{}
+
+// NewWatchStatsContext returns a copy of the context carrying the given watch stats
+// Sessions include the stats of a watch's context in the logs of its stream's failures.
+func NewWatchStatsContext(ctx context.Context, stats *WatchStats) context.Context {
+	return context.WithValue(ctx, watchStatsKey{}, stats)
+}
+
+// WatchStatsFromContext returns the watch stats carried by the given context, or nil if there are none
+func WatchStatsFromContext(ctx context.Context) *WatchStats {
+	stats, _ := ctx.Value(watchStatsKey{}).(*WatchStats)
+	return stats
+}
+
+// StreamError records the error that closed a watch's stream
+// Sessions record the cause of a stream that is closed before the watch's context is canceled, so the watch can
+// report why it stopped. Only the first error is kept. Record may be called on a nil StreamError, in which case
+// nothing is recorded.
+type StreamError struct {
+	mu  sync.Mutex
+	err error
+}
+
+// Err returns the error that closed the stream, or nil if no error has been recorded
+func (e *StreamError) Err() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.err
+}
+
+// Record records the error that closed the stream
+func (e *StreamError) Record(err error) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+type streamErrorKey struct{}
+
+// NewStreamErrorContext returns a copy of the context carrying the given stream error
+// Sessions record the error that closes a stream opened with the context before closing the stream's channel.
+func NewStreamErrorContext(ctx context.Context, err *StreamError) context.Context {
+	return context.WithValue(ctx, streamErrorKey{}, err)
+}
+
+// StreamErrorFromContext returns the stream error carried by the given context, or nil if there is none
+func StreamErrorFromContext(ctx context.Context) *StreamError {
+	err, _ := ctx.Value(streamErrorKey{}).(*StreamError)
+	return err
+}
@@ -0,0 +1,69 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatchStats(t *testing.T) {
+	stats := &WatchStats{}
+	assert.Equal(t, uint64(0), stats.Delivered())
+	assert.Equal(t, time.Duration(0), stats.SinceLastEvent())
+
+	// Stats may be recorded and read concurrently
+	calls := 0
+	dropped := stats.DroppedFunc(func() {
+		calls++
+	})
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= 100; i++ {
+			stats.RecordVersion(uint64(i))
+			stats.RecordDelivered()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			stats.Delivered()
+			stats.SinceLastEvent()
+		}
+	}()
+	dropped()
+	wg.Wait()
+
+	assert.Equal(t, uint64(100), stats.Delivered())
+	assert.Equal(t, uint64(1), stats.Dropped())
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, uint64(100), stats.LastVersion())
+	assert.True(t, stats.SinceLastEvent() > 0)
+	assert.Equal(t, []interface{}{"delivered", uint64(100), "dropped", uint64(1), "lastVersion", uint64(100)}, stats.Fields()[:6])
+
+	// Nil stats record nothing
+	var nilStats *WatchStats
+	nilStats.RecordDelivered()
+	nilStats.RecordVersion(1)
+	assert.Nil(t, nilStats.DroppedFunc(nil))
+
+	assert.Nil(t, WatchStatsFromContext(context.TODO()))
+	assert.Equal(t, stats, WatchStatsFromContext(NewWatchStatsContext(context.TODO(), stats)))
+}
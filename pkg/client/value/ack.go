@@ -0,0 +1,71 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package value
+
+import (
+	"context"
+	"time"
+)
+
+func (v *value) WatchAck(ctx context.Context, handler func(*Event) error, opts ...WatchOption) error {
+	return WatchAck(ctx, v, handler, opts...)
+}
+
+// WatchAck watches the given value, passing each event to the handler in order
+// The next event is not pulled from the watch stream until the handler returns nil, so a slow handler applies
+// back-pressure to the stream. If the handler returns an error, the same event is redelivered as configured by
+// WithAckRetry; once retries are exhausted the watch is closed and the handler's error is returned. Events are
+// therefore delivered at least once while WatchAck is running, but events that occur after it returns are not
+// replayed to a later WatchAck. WithBuffer decouples the stream from the handler and should not be combined with
+// WatchAck. WatchAck blocks until the context is canceled, returning the context's error, or the watch is closed.
+func WatchAck(ctx context.Context, v Value, handler func(*Event) error, opts ...WatchOption) error {
+	options := &watchOptions{}
+	for _, opt := range opts {
+		opt.applyWatch(options)
+	}
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ch := make(chan *Event)
+	if err := v.Watch(watchCtx, ch, opts...); err != nil {
+		return err
+	}
+
+	for event := range ch {
+		if err := ack(ctx, event, handler, options); err != nil {
+			// Close the watch and drain the channel to release the stream
+			cancel()
+			for range ch {
+			}
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// ack delivers the event to the handler until it's acknowledged or retries are exhausted
+func ack(ctx context.Context, event *Event, handler func(*Event) error, options *watchOptions) error {
+	for attempt := 0; ; attempt++ {
+		err := handler(event)
+		if err == nil || attempt >= options.retries {
+			return err
+		}
+		select {
+		case <-time.After(options.retryDelay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package value
+
+import (
+	"context"
+	"github.com/atomix/go-client/pkg/client/util"
+)
+
+// getAllConcurrency is the maximum number of concurrent Gets issued by GetAll
+const getAllConcurrency = 16
+
+// Result is the result of getting a single Value in GetAll
+type Result struct {
+	// Value is the current value, or nil if the value is not set or the Get failed
+	Value []byte
+
+	// Version is the current version, or 0 if the value has never been set or the Get failed
+	Version uint64
+
+	// Err is the error returned by the Get, if any
+	Err error
+}
+
+// GetAll gets the current value and version of each of the given values concurrently
+// A result is returned for each value in the order in which the values were given. A failure to get one value
+// is reported in that value's Result and does not fail the others. The returned error is only set if the
+// context was canceled or its deadline exceeded before all values were retrieved.
+func GetAll(ctx context.Context, values []Value) ([]Result, error) {
+	results := make([]Result, len(values))
+	util.IterLimitedAsync(len(values), getAllConcurrency, func(i int) {
+		value, version, err := values[i].Get(ctx)
+		results[i] = Result{
+			Value:   value,
+			Version: version,
+			Err:     err,
+		}
+	})
+	return results, ctx.Err()
+}
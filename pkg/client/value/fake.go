@@ -0,0 +1,242 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package value
+
+import (
+	"bytes"
+	"context"
+	api "github.com/atomix/api/proto/atomix/value"
+	"github.com/atomix/go-client/pkg/client/primitive"
+	"github.com/atomix/go-client/pkg/client/session"
+	"github.com/atomix/go-client/pkg/client/util"
+	"github.com/atomix/go-client/pkg/client/value/internal/fake"
+	"sync"
+)
+
+// fakeWatchBuffer is the number of events buffered for each watcher of a fake value unless WithBuffer is provided
+const fakeWatchBuffer = 1000
+
+func init() {
+	fake.NewValue = func(name primitive.Name) interface{} {
+		return newFake(name)
+	}
+}
+
+// newFake returns an in-memory Value for testing code that depends on Value
+// The fake is exported by the valuetest package.
+func newFake(name primitive.Name) Value {
+	return &fakeValue{
+		Base:     primitive.NewBase(Type, name),
+		watchers: make(map[chan<- *Event]*fakeWatcher),
+	}
+}
+
+// fakeValue is an in-memory implementation of Value
+type fakeValue struct {
+	primitive.Base
+	value     []byte
+	version   uint64
+	deleted   bool
+	watchers  map[chan<- *Event]*fakeWatcher
+	pending   []fakePush
+	mu        sync.Mutex
+	publishMu sync.Mutex
+}
+
+// fakePush is an event to be pushed to a watcher once the value's lock is released
+type fakePush struct {
+	watcher *fakeWatcher
+	event   *Event
+}
+
+// fakeWatcher is a watcher of a fake value
+type fakeWatcher struct {
+	options *watchOptions
+	filter  *noopFilter
+	buffer  *util.EventBuffer
+}
+
+func (v *fakeValue) Sessions() []*session.Session {
+	return nil
+}
+
+func (v *fakeValue) Set(ctx context.Context, value []byte, opts ...SetOption) (uint64, error) {
+	opts = expandSetOptions(opts)
+	request := &api.SetRequest{
+		Value: value,
+	}
+	for i := range opts {
+		opts[i].beforeSet(request)
+	}
+
+	var version uint64
+	err := v.write(func() error {
+		if (request.ExpectVersion != 0 && request.ExpectVersion != v.version) ||
+			(len(request.ExpectValue) > 0 && !bytes.Equal(request.ExpectValue, v.value)) {
+			return mismatchError(request)
+		}
+		v.set(value)
+		version = v.version
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	response := &api.SetResponse{
+		Version:   version,
+		Succeeded: true,
+	}
+	for i := range opts {
+		opts[i].afterSet(response)
+	}
+	return version, nil
+}
+
+func (v *fakeValue) Get(ctx context.Context, opts ...GetOption) ([]byte, uint64, error) {
+	v.mu.Lock()
+	value, version := v.value, v.version
+	v.mu.Unlock()
+
+	options := &getOptions{}
+	for _, opt := range opts {
+		opt.applyGet(options)
+	}
+	if options.stale != nil {
+		*options.stale = false
+	}
+	if options.migrator != nil {
+		value, version := migrate(value, version, options.migrator, func(value []byte, version uint64) (uint64, error) {
+			return v.Set(ctx, value, IfVersion(version))
+		})
+		return value, version, nil
+	}
+	return value, version, nil
+}
+
+func (v *fakeValue) GetAndSet(ctx context.Context, value []byte) ([]byte, uint64, uint64, error) {
+	var prev []byte
+	var prevVersion, version uint64
+	_ = v.write(func() error {
+		prev, prevVersion = v.value, v.version
+		v.set(value)
+		version = v.version
+		return nil
+	})
+	return prev, prevVersion, version, nil
+}
+
+// write calls f while holding the value's lock and then pushes the events published by f to watchers
+// Events are pushed once the lock is released so a slow or abandoned watcher cannot block the value's readers or
+// the cleanup of canceled watches. The publish lock keeps events in the order the writes were applied.
+func (v *fakeValue) write(f func() error) error {
+	v.mu.Lock()
+	err := f()
+	pending := v.pending
+	v.pending = nil
+	v.publishMu.Lock()
+	v.mu.Unlock()
+	defer v.publishMu.Unlock()
+	for _, push := range pending {
+		push.watcher.buffer.Push(push.event)
+	}
+	return err
+}
+
+// set updates the value and queues the change to be pushed to watchers
+// The caller must hold the value's lock, and the queued events are pushed by write.
+func (v *fakeValue) set(value []byte) {
+	v.value = value
+	v.version++
+	event := newEvent(&api.EventResponse{
+		Type:       api.EventResponse_UPDATED,
+		NewValue:   value,
+		NewVersion: v.version,
+	})
+	for _, watcher := range v.watchers {
+		if watcher.options.accepts(event.Type) && !watcher.filter.isNoop(value) {
+			v.pending = append(v.pending, fakePush{watcher: watcher, event: event})
+		}
+	}
+}
+
+func (v *fakeValue) Watch(ctx context.Context, ch chan<- *Event, opts ...WatchOption) error {
+	options := &watchOptions{}
+	for _, opt := range opts {
+		opt.applyWatch(options)
+	}
+	size, policy := fakeWatchBuffer, util.OverflowBlock
+	if options.bufferSize > 0 {
+		size, policy = options.bufferSize, options.overflow
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if _, ok := v.watchers[ch]; ok {
+		return ErrAlreadyWatching
+	}
+	watcher := &fakeWatcher{
+		options: options,
+		filter:  &noopFilter{equal: options.equal},
+		buffer: util.NewEventBuffer(size, policy, func(event interface{}) {
+			// Events buffered for a canceled watch are discarded so an abandoned watcher doesn't block writers
+			select {
+			case <-ctx.Done():
+			default:
+				select {
+				case ch <- event.(*Event):
+					options.stats.RecordVersion(event.(*Event).Version)
+					options.stats.RecordDelivered()
+				case <-ctx.Done():
+				}
+			}
+		}, options.stats.DroppedFunc(options.dropped)),
+	}
+	v.watchers[ch] = watcher
+
+	go func() {
+		<-ctx.Done()
+		v.mu.Lock()
+		delete(v.watchers, ch)
+		v.mu.Unlock()
+		watcher.buffer.Close()
+		close(ch)
+	}()
+	return nil
+}
+
+func (v *fakeValue) Update(ctx context.Context, fn func(old []byte, version uint64) ([]byte, error), opts ...UpdateOption) (uint64, error) {
+	return Update(ctx, v, fn, opts...)
+}
+
+func (v *fakeValue) WatchAck(ctx context.Context, handler func(*Event) error, opts ...WatchOption) error {
+	return WatchAck(ctx, v, handler, opts...)
+}
+
+func (v *fakeValue) Close() error {
+	return nil
+}
+
+func (v *fakeValue) Delete() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.deleted {
+		return ErrNotFound
+	}
+	v.value = nil
+	v.version = 0
+	v.deleted = true
+	return nil
+}
@@ -0,0 +1,171 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package value
+
+import (
+	"context"
+	"github.com/atomix/go-client/pkg/client/primitive"
+	"github.com/atomix/go-client/pkg/client/util"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestFakeValue(t *testing.T) {
+	value := newFake(primitive.NewName("default", "test", "default", "test"))
+	assert.Equal(t, Type, value.Metadata().Type)
+	assert.Len(t, value.Sessions(), 0)
+
+	val, version, err := value.Get(context.TODO())
+	assert.NoError(t, err)
+	assert.Nil(t, val)
+	assert.Equal(t, uint64(0), version)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan *Event)
+	err = value.Watch(ctx, ch)
+	assert.NoError(t, err)
+	err = value.Watch(ctx, ch)
+	assert.Equal(t, ErrAlreadyWatching, err)
+
+	_, err = value.Set(context.TODO(), []byte("foo"), IfVersion(1))
+	assert.Equal(t, ErrVersionMismatch, err)
+
+	version, err = value.Set(context.TODO(), []byte("foo"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), version)
+
+	_, err = value.Set(context.TODO(), []byte("bar"), IfValue([]byte("bar")))
+	assert.Equal(t, ErrValueMismatch, err)
+	_, err = value.Set(context.TODO(), []byte("bar"), IfVersion(1), IfValue([]byte("bar")))
+	assert.Equal(t, ErrPreconditionFailed, err)
+
+	version, err = value.Set(context.TODO(), []byte("bar"), IfVersion(1), IfValue([]byte("foo")))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), version)
+
+	prev, prevVersion, version, err := value.GetAndSet(context.TODO(), []byte("baz"))
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", string(prev))
+	assert.Equal(t, uint64(2), prevVersion)
+	assert.Equal(t, uint64(3), version)
+
+	val, version, err = value.Get(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, "baz", string(val))
+	assert.Equal(t, uint64(3), version)
+
+	event := <-ch
+	assert.Equal(t, EventUpdated, event.Type)
+	assert.Equal(t, "foo", string(event.Value))
+	assert.Equal(t, uint64(1), event.Version)
+	event = <-ch
+	assert.Equal(t, "bar", string(event.Value))
+	assert.Equal(t, uint64(2), event.Version)
+	event = <-ch
+	assert.Equal(t, "baz", string(event.Value))
+	assert.Equal(t, uint64(3), event.Version)
+
+	cancel()
+	_, ok := <-ch
+	assert.False(t, ok)
+
+	assert.NoError(t, value.Delete())
+	_, version, err = value.Get(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), version)
+	assert.Equal(t, ErrNotFound, value.Delete())
+}
+
+func TestFakeValueAbandonedWatch(t *testing.T) {
+	value := newFake(primitive.NewName("default", "test", "default", "test"))
+
+	// A watcher that stops reading and is canceled must not block writers
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan *Event)
+	err := value.Watch(ctx, ch, WithBuffer(1, util.OverflowBlock))
+	assert.NoError(t, err)
+
+	written := make(chan struct{})
+	go func() {
+		for i := 0; i < 5; i++ {
+			_, err := value.Set(context.TODO(), []byte("foo"))
+			assert.NoError(t, err)
+		}
+		close(written)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-written:
+	case <-time.After(5 * time.Second):
+		t.Fatal("writes blocked by an abandoned watcher")
+	}
+	_, version, err := value.Get(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), version)
+}
+
+func TestFakeValueWatchOptions(t *testing.T) {
+	value := newFake(primitive.NewName("default", "test", "default", "test"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := make(chan *Event)
+	err := value.Watch(ctx, ch, WithSuppressNoop(), WithFilter(EventUpdated))
+	assert.NoError(t, err)
+
+	_, err = value.Set(context.TODO(), []byte("foo"))
+	assert.NoError(t, err)
+	_, err = value.Set(context.TODO(), []byte("foo"))
+	assert.NoError(t, err)
+	_, err = value.Set(context.TODO(), []byte("bar"))
+	assert.NoError(t, err)
+
+	event := <-ch
+	assert.Equal(t, "foo", string(event.Value))
+	assert.Equal(t, uint64(1), event.Version)
+	event = <-ch
+	assert.Equal(t, "bar", string(event.Value))
+	assert.Equal(t, uint64(3), event.Version)
+}
+
+func TestFakeValueWatchStats(t *testing.T) {
+	value := newFake(primitive.NewName("default", "test", "default", "test"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan *Event)
+	stats := &util.WatchStats{}
+	err := value.Watch(ctx, ch, WithWatchStats(stats))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), stats.SinceLastEvent())
+
+	_, err = value.Set(context.TODO(), []byte("foo"))
+	assert.NoError(t, err)
+	_, err = value.Set(context.TODO(), []byte("bar"))
+	assert.NoError(t, err)
+	<-ch
+	<-ch
+
+	// Canceling the watch waits for the last delivery to be recorded
+	cancel()
+	for range ch {
+	}
+	assert.Equal(t, uint64(2), stats.Delivered())
+	assert.Equal(t, uint64(0), stats.Dropped())
+	assert.Equal(t, uint64(2), stats.LastVersion())
+	assert.True(t, stats.SinceLastEvent() > 0)
+}
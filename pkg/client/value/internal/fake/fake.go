@@ -0,0 +1,24 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import (
+	"github.com/atomix/go-client/pkg/client/primitive"
+)
+
+// NewValue returns a new in-memory value.Value
+// NewValue is set by the value package, whose unexported option types the fake depends on, and is exported
+// to applications by the valuetest package.
+var NewValue func(name primitive.Name) interface{}
@@ -15,7 +15,11 @@
 package value
 
 import (
+	"bytes"
 	api "github.com/atomix/api/proto/atomix/value"
+	"github.com/atomix/go-client/pkg/client/util"
+	"sync/atomic"
+	"time"
 )
 
 // SetOption is an option for Set calls
@@ -57,3 +61,229 @@ func (o versionOption) beforeSet(request *api.SetRequest) {
 func (o versionOption) afterSet(response *api.SetResponse) {
 
 }
+
+// SetPreset is a reusable bundle of Set options that is applied as a single option
+// The preset's options are expanded in order in place of the preset, so options passed to Set after a preset
+// override the preset's options, e.g. Set(ctx, value, preset, IfVersion(version)). Presets may be nested.
+type SetPreset []SetOption
+
+func (p SetPreset) beforeSet(request *api.SetRequest) {
+	for _, opt := range p {
+		opt.beforeSet(request)
+	}
+}
+
+func (p SetPreset) afterSet(response *api.SetResponse) {
+	for _, opt := range p {
+		opt.afterSet(response)
+	}
+}
+
+// expandSetOptions flattens any presets in the given options into their underlying options, preserving order
+func expandSetOptions(opts []SetOption) []SetOption {
+	expanded := make([]SetOption, 0, len(opts))
+	for _, opt := range opts {
+		if preset, ok := opt.(SetPreset); ok {
+			expanded = append(expanded, expandSetOptions(preset)...)
+		} else {
+			expanded = append(expanded, opt)
+		}
+	}
+	return expanded
+}
+
+// WatchOption is an option for Watch calls
+type WatchOption interface {
+	applyWatch(options *watchOptions)
+}
+
+// watchOptions is the options for Watch calls
+type watchOptions struct {
+	equal      func(a, b []byte) bool
+	bufferSize int
+	overflow   util.OverflowPolicy
+	dropped    func()
+	types      map[EventType]bool
+	retries    int
+	retryDelay time.Duration
+	stats      *util.WatchStats
+}
+
+// accepts returns whether events of the given type pass the watch's filter
+func (o *watchOptions) accepts(t EventType) bool {
+	return o.types == nil || o.types[t]
+}
+
+// WithSuppressNoop returns a Watch option to drop events whose value is byte-identical to the last delivered value
+// Suppressing no-op events requires the watcher to retain the last delivered value in memory.
+func WithSuppressNoop() WatchOption {
+	return suppressNoopOption{equal: bytes.Equal}
+}
+
+// WithSuppressNoopFunc returns a Watch option to drop events whose value is equal to the last delivered value
+// The given function is used to compare structured values, e.g. to ignore differences in encoding. Suppressing
+// no-op events requires the watcher to retain the last delivered value in memory.
+func WithSuppressNoopFunc(equal func(a, b []byte) bool) WatchOption {
+	return suppressNoopOption{equal: equal}
+}
+
+type suppressNoopOption struct {
+	equal func(a, b []byte) bool
+}
+
+func (o suppressNoopOption) applyWatch(options *watchOptions) {
+	options.equal = o.equal
+}
+
+// WithBuffer returns a Watch option to buffer up to size events between the watch stream and the channel
+// By default events are sent directly to the channel, so a slow consumer blocks the stream. With a buffer, events
+// that arrive while the buffer is full are handled according to the given policy: util.OverflowBlock blocks the
+// stream as before, while util.OverflowDropOldest and util.OverflowDropNewest keep the stream flowing at the cost
+// of dropping events. Use WithDropCounter to detect dropped events.
+func WithBuffer(size int, policy util.OverflowPolicy) WatchOption {
+	return bufferOption{size: size, policy: policy}
+}
+
+type bufferOption struct {
+	size   int
+	policy util.OverflowPolicy
+}
+
+func (o bufferOption) applyWatch(options *watchOptions) {
+	options.bufferSize = o.size
+	options.overflow = o.policy
+}
+
+// WithFilter returns a Watch option to only deliver events of the given types
+// The value service cannot filter events, so events are filtered by the client before they reach the channel.
+func WithFilter(types ...EventType) WatchOption {
+	return filterOption{types: types}
+}
+
+type filterOption struct {
+	types []EventType
+}
+
+func (o filterOption) applyWatch(options *watchOptions) {
+	options.types = make(map[EventType]bool)
+	for _, t := range o.types {
+		options.types[t] = true
+	}
+}
+
+// WithDropCounter returns a Watch option to atomically increment the given counter for each dropped event
+func WithDropCounter(counter *uint64) WatchOption {
+	return dropCounterOption{counter: counter}
+}
+
+type dropCounterOption struct {
+	counter *uint64
+}
+
+func (o dropCounterOption) applyWatch(options *watchOptions) {
+	options.dropped = func() {
+		atomic.AddUint64(o.counter, 1)
+	}
+}
+
+// WithWatchStats returns a Watch option to track the delivery of the watch's events in the given stats
+// The stats count the events delivered to the channel and dropped by the buffer, and record the version and time
+// of the last delivered event. They're updated atomically and may be read while the watch is running. The stats
+// are also included in the session's logs when the watch's stream fails.
+func WithWatchStats(stats *util.WatchStats) WatchOption {
+	return watchStatsOption{stats: stats}
+}
+
+type watchStatsOption struct {
+	stats *util.WatchStats
+}
+
+func (o watchStatsOption) applyWatch(options *watchOptions) {
+	options.stats = o.stats
+}
+
+// WithAckRetry returns a Watch option to redeliver an event to a WatchAck handler that returns an error
+// The event is redelivered up to the given number of times, waiting for the given delay before each redelivery.
+// By default WatchAck returns the handler's first error.
+func WithAckRetry(retries int, delay time.Duration) WatchOption {
+	return ackRetryOption{retries: retries, delay: delay}
+}
+
+type ackRetryOption struct {
+	retries int
+	delay   time.Duration
+}
+
+func (o ackRetryOption) applyWatch(options *watchOptions) {
+	options.retries = o.retries
+	options.retryDelay = o.delay
+}
+
+// GetOption is an option for Get calls
+type GetOption interface {
+	applyGet(options *getOptions)
+}
+
+// getOptions is the options for Get calls
+type getOptions struct {
+	migrator func(old []byte, version uint64) ([]byte, bool)
+	stale    *bool
+}
+
+// WithMigrator returns a Get option to upgrade values read in an old format
+// The migrator is called with the value and version read and returns the upgraded value and a bool indicating
+// whether the value was upgraded. Upgraded values are written back to the primitive with a set conditional on
+// the version read. If the value was concurrently updated, the write-back fails and is ignored since the
+// concurrent write is expected to be in the current format. Unset values are never written back.
+func WithMigrator(migrator func(old []byte, version uint64) ([]byte, bool)) GetOption {
+	return migratorOption{migrator: migrator}
+}
+
+type migratorOption struct {
+	migrator func(old []byte, version uint64) ([]byte, bool)
+}
+
+func (o migratorOption) applyGet(options *getOptions) {
+	options.migrator = o.migrator
+}
+
+// WithStaleFallback returns a Get option to read from a replica if the partition's leader is unavailable
+// Replicas are configured with session.WithReplicas. If the leader is unavailable, the value is read from a replica
+// without waiting for the replica to catch up with the session's writes, so the value may not reflect a preceding
+// Set, and stale is set to true. Otherwise, stale is set to false. Without this option, Get never reads from a
+// replica while the leader is unavailable.
+func WithStaleFallback(stale *bool) GetOption {
+	return staleFallbackOption{stale: stale}
+}
+
+type staleFallbackOption struct {
+	stale *bool
+}
+
+func (o staleFallbackOption) applyGet(options *getOptions) {
+	options.stale = o.stale
+}
+
+// UpdateOption is an option for Update calls
+type UpdateOption interface {
+	applyUpdate(options *updateOptions)
+}
+
+// updateOptions is the options for Update calls
+type updateOptions struct {
+	retries int
+}
+
+// WithUpdateRetries returns an Update option to bound the number of times a conflicting write is retried
+// By default a conflicting write is retried up to 100 times.
+func WithUpdateRetries(retries int) UpdateOption {
+	return updateRetriesOption{retries: retries}
+}
+
+type updateRetriesOption struct {
+	retries int
+}
+
+func (o updateRetriesOption) applyUpdate(options *updateOptions) {
+	options.retries = o.retries
+}
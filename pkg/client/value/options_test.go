@@ -16,8 +16,10 @@ package value
 
 import (
 	api "github.com/atomix/api/proto/atomix/value"
+	"github.com/atomix/go-client/pkg/client/util"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestOptions(t *testing.T) {
@@ -28,4 +30,64 @@ func TestOptions(t *testing.T) {
 	IfVersion(uint64(1)).beforeSet(request)
 	assert.Equal(t, "foo", string(request.ExpectValue))
 	assert.Equal(t, uint64(1), request.ExpectVersion)
+
+	options := &watchOptions{}
+	assert.Nil(t, options.equal)
+	WithSuppressNoop().applyWatch(options)
+	assert.NotNil(t, options.equal)
+	assert.Equal(t, 0, options.bufferSize)
+	WithBuffer(10, util.OverflowDropNewest).applyWatch(options)
+	assert.Equal(t, 10, options.bufferSize)
+	assert.Equal(t, util.OverflowDropNewest, options.overflow)
+	var dropped uint64
+	WithDropCounter(&dropped).applyWatch(options)
+	options.dropped()
+	assert.Equal(t, uint64(1), dropped)
+	assert.True(t, options.accepts(EventUpdated))
+	WithFilter().applyWatch(options)
+	assert.False(t, options.accepts(EventUpdated))
+	WithFilter(EventUpdated).applyWatch(options)
+	assert.True(t, options.accepts(EventUpdated))
+	assert.Equal(t, 0, options.retries)
+	WithAckRetry(3, time.Second).applyWatch(options)
+	assert.Equal(t, 3, options.retries)
+	assert.Equal(t, time.Second, options.retryDelay)
+
+	getOptions := &getOptions{}
+	assert.Nil(t, getOptions.migrator)
+	WithMigrator(func(old []byte, version uint64) ([]byte, bool) {
+		return old, false
+	}).applyGet(getOptions)
+	assert.NotNil(t, getOptions.migrator)
+	assert.Nil(t, getOptions.stale)
+	stale := true
+	WithStaleFallback(&stale).applyGet(getOptions)
+	assert.Equal(t, &stale, getOptions.stale)
+}
+
+func TestSetPreset(t *testing.T) {
+	preset := SetPreset{IfValue([]byte("foo")), IfVersion(1)}
+
+	request := &api.SetRequest{}
+	preset.beforeSet(request)
+	assert.Equal(t, "foo", string(request.ExpectValue))
+	assert.Equal(t, uint64(1), request.ExpectVersion)
+
+	// Options following the preset override the preset's options
+	opts := expandSetOptions([]SetOption{preset, IfVersion(2)})
+	assert.Len(t, opts, 3)
+	request = &api.SetRequest{}
+	for _, opt := range opts {
+		opt.beforeSet(request)
+	}
+	assert.Equal(t, "foo", string(request.ExpectValue))
+	assert.Equal(t, uint64(2), request.ExpectVersion)
+
+	// Nested presets are expanded in order
+	opts = expandSetOptions([]SetOption{IfVersion(3), SetPreset{preset, IfValue([]byte("bar"))}})
+	assert.Len(t, opts, 4)
+	assert.Equal(t, IfVersion(3), opts[0])
+	assert.Equal(t, IfValue([]byte("foo")), opts[1])
+	assert.Equal(t, IfVersion(1), opts[2])
+	assert.Equal(t, IfValue([]byte("bar")), opts[3])
 }
@@ -0,0 +1,69 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package value
+
+import (
+	"context"
+	"errors"
+)
+
+// defaultUpdateRetries is the number of times Update retries a conflicting write unless WithUpdateRetries is provided
+const defaultUpdateRetries = 100
+
+// ErrAbort is returned by an Update function to stop the update without writing the value
+var ErrAbort = errors.New("update aborted")
+
+func (v *value) Update(ctx context.Context, fn func(old []byte, version uint64) ([]byte, error), opts ...UpdateOption) (uint64, error) {
+	return Update(ctx, v, fn, opts...)
+}
+
+// Update performs an optimistic read-modify-write of the given value
+// The current value and version are read and passed to fn to compute the new value, which is then set conditionally
+// on the version read. If the value was concurrently updated, the update is retried from the read up to the number
+// of times configured by WithUpdateRetries, after which ErrVersionMismatch is returned. If fn returns ErrAbort, the
+// update stops without writing and ErrAbort is returned; any other error from fn is returned as is. As with
+// GetAndSet, a write to an unset value cannot be made conditional, so a concurrent write that initializes the
+// value may be overwritten. Returns the new version on success.
+func Update(ctx context.Context, v Value, fn func(old []byte, version uint64) ([]byte, error), opts ...UpdateOption) (uint64, error) {
+	options := &updateOptions{
+		retries: defaultUpdateRetries,
+	}
+	for _, opt := range opts {
+		opt.applyUpdate(options)
+	}
+
+	for attempt := 0; ; attempt++ {
+		old, version, err := v.Get(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		update, err := fn(old, version)
+		if err != nil {
+			return 0, err
+		}
+
+		var setOpts []SetOption
+		if version > 0 {
+			setOpts = append(setOpts, IfVersion(version))
+		}
+		newVersion, err := v.Set(ctx, update, setOpts...)
+		if err == nil {
+			return newVersion, nil
+		} else if err != ErrVersionMismatch || attempt >= options.retries {
+			return 0, err
+		}
+	}
+}
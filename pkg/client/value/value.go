@@ -24,11 +24,40 @@ import (
 	"github.com/atomix/go-client/pkg/client/util"
 	"github.com/atomix/go-client/pkg/client/util/net"
 	"google.golang.org/grpc"
+	"sync"
 )
 
 // Type is the value type
 const Type primitive.Type = "Value"
 
+// Operation names used to identify value operations in traces and metrics
+const (
+	setOp   = "value.Set"
+	getOp   = "value.Get"
+	watchOp = "value.Watch"
+)
+
+// ErrAlreadyWatching is returned by Watch when the given channel is already watching the value
+var ErrAlreadyWatching = errors.New("channel is already watching the value")
+
+var (
+	// ErrVersionMismatch is returned by Set when the IfVersion condition is not met
+	ErrVersionMismatch = errors.New("version mismatch")
+
+	// ErrValueMismatch is returned by Set when the IfValue condition is not met
+	ErrValueMismatch = errors.New("value mismatch")
+
+	// ErrPreconditionFailed is returned by Set when both the IfVersion and IfValue conditions are given and
+	// either of them is not met. The value service does not report which condition failed.
+	ErrPreconditionFailed = errors.New("precondition failed")
+
+	// ErrNotApplied is returned by Set when the service rejects a write that had no conditions
+	ErrNotApplied = errors.New("set was not applied")
+)
+
+// ErrNotFound is returned by Delete when the primitive does not exist
+var ErrNotFound = primitive.ErrNotFound
+
 // Client provides an API for creating Values
 type Client interface {
 	// GetValue gets the Value instance of the given name
@@ -39,14 +68,34 @@ type Client interface {
 type Value interface {
 	primitive.Primitive
 
+	// Sessions returns the sessions used by the primitive, one for each partition in which it's stored
+	Sessions() []*session.Session
+
 	// Set sets the current value and returns the version
+	// If the IfVersion or IfValue condition is not met, ErrVersionMismatch or ErrValueMismatch is returned. If both
+	// conditions are given and either is not met, ErrPreconditionFailed is returned.
 	Set(ctx context.Context, value []byte, opts ...SetOption) (uint64, error)
 
 	// Get gets the current value and version
-	Get(ctx context.Context) ([]byte, uint64, error)
+	Get(ctx context.Context, opts ...GetOption) ([]byte, uint64, error)
+
+	// GetAndSet sets the current value and returns the previous value and version along with the new version
+	GetAndSet(ctx context.Context, value []byte) ([]byte, uint64, uint64, error)
+
+	// Update performs an optimistic read-modify-write of the value, returning the new version
+	// The function is called with the current value and version to compute the new value. The update is retried if
+	// the value is concurrently updated, and stops without writing if the function returns ErrAbort.
+	Update(ctx context.Context, fn func(old []byte, version uint64) ([]byte, error), opts ...UpdateOption) (uint64, error)
 
 	// Watch watches the value for changes
-	Watch(ctx context.Context, ch chan<- *Event) error
+	// A channel may only watch the value once at a time. Registering a channel that is already watching the
+	// value returns ErrAlreadyWatching.
+	Watch(ctx context.Context, ch chan<- *Event, opts ...WatchOption) error
+
+	// WatchAck watches the value for changes, passing each event to the given handler
+	// The next event is not received until the handler has acknowledged the current event by returning nil. An
+	// event whose handler fails is redelivered as configured by WithAckRetry.
+	WatchAck(ctx context.Context, handler func(*Event) error, opts ...WatchOption) error
 }
 
 // EventType is the type of a set event
@@ -72,7 +121,7 @@ type Event struct {
 // New creates a new Lock primitive for the given partitions
 // The value will be created in one of the given partitions.
 func New(ctx context.Context, name primitive.Name, partitions []net.Address, opts ...session.Option) (Value, error) {
-	i, err := util.GetPartitionIndex(name.Name, len(partitions))
+	i, err := session.GetPartitioner(opts...)(name.Name, len(partitions))
 	if err != nil {
 		return nil, err
 	}
@@ -86,28 +135,32 @@ func newValue(ctx context.Context, name primitive.Name, address net.Address, opt
 		return nil, err
 	}
 	return &value{
-		name:    name,
-		session: sess,
+		Base:     primitive.NewBase(Type, name),
+		session:  sess,
+		watchers: make(map[chan<- *Event]bool),
 	}, nil
 }
 
 // value is the single partition implementation of Lock
 type value struct {
-	name    primitive.Name
-	session *session.Session
+	primitive.Base
+	session  *session.Session
+	watchers map[chan<- *Event]bool
+	mu       sync.Mutex
 }
 
-func (v *value) Name() primitive.Name {
-	return v.name
+func (v *value) Sessions() []*session.Session {
+	return []*session.Session{v.session}
 }
 
 func (v *value) Set(ctx context.Context, value []byte, opts ...SetOption) (uint64, error) {
+	opts = expandSetOptions(opts)
 	request := &api.SetRequest{}
 	for i := range opts {
 		opts[i].beforeSet(request)
 	}
 
-	r, err := v.session.DoCommand(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+	r, err := v.session.DoCommand(ctx, setOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewValueServiceClient(conn)
 		request := &api.SetRequest{
 			Header: header,
@@ -131,17 +184,27 @@ func (v *value) Set(ctx context.Context, value []byte, opts ...SetOption) (uint6
 
 	response := r.(*api.SetResponse)
 	if !response.Succeeded {
-		if request.ExpectVersion > 0 {
-			return 0, errors.New("version mismatch")
-		}
-		return 0, errors.New("value mismatch")
+		return 0, mismatchError(request)
 	}
 
 	return response.Version, nil
 }
 
-func (v *value) Get(ctx context.Context) ([]byte, uint64, error) {
-	r, err := v.session.DoQuery(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
+func (v *value) Get(ctx context.Context, opts ...GetOption) ([]byte, uint64, error) {
+	options := &getOptions{}
+	for _, opt := range opts {
+		opt.applyGet(options)
+	}
+
+	query := v.session.DoQuery
+	if options.stale != nil {
+		query = func(ctx context.Context, name string, f func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error)) (interface{}, error) {
+			response, stale, err := v.session.DoQueryWithFallback(ctx, name, f)
+			*options.stale = stale
+			return response, err
+		}
+	}
+	r, err := query(ctx, getOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (*headers.ResponseHeader, interface{}, error) {
 		client := api.NewValueServiceClient(conn)
 		request := &api.GetRequest{
 			Header: header,
@@ -157,11 +220,107 @@ func (v *value) Get(ctx context.Context) ([]byte, uint64, error) {
 	}
 
 	response := r.(*api.GetResponse)
+
+	if options.migrator != nil {
+		value, version := migrate(response.Value, response.Version, options.migrator, func(value []byte, version uint64) (uint64, error) {
+			return v.Set(ctx, value, IfVersion(version))
+		})
+		return value, version, nil
+	}
 	return response.Value, response.Version, nil
 }
 
-func (v *value) Watch(ctx context.Context, ch chan<- *Event) error {
-	stream, err := v.session.DoCommandStream(ctx, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
+// GetAndSet is implemented as an optimistic read-modify-write loop since the value service does not return the
+// previous value from Set. The value is read and then set conditionally on the version read, retrying if the value
+// was concurrently updated. If the value was unset when read, the set cannot be made conditional, so a concurrent
+// write that initializes the value may be overwritten and not returned as the previous value.
+func (v *value) GetAndSet(ctx context.Context, value []byte) ([]byte, uint64, uint64, error) {
+	for {
+		prev, prevVersion, err := v.Get(ctx)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+
+		var opts []SetOption
+		if prevVersion > 0 {
+			opts = append(opts, IfVersion(prevVersion))
+		}
+		newVersion, err := v.Set(ctx, value, opts...)
+		if err == nil {
+			return prev, prevVersion, newVersion, nil
+		} else if err != ErrVersionMismatch {
+			return nil, 0, 0, err
+		}
+	}
+}
+
+// mismatchError returns the error for a Set that failed the given request's conditions
+// The value service does not report which condition failed. If only one condition was set, it must be the one
+// that failed. If both were set, the cause can't be determined without reading the value again, which would race
+// with other writers, so ErrPreconditionFailed is returned. A Set without conditions returns ErrNotApplied.
+func mismatchError(request *api.SetRequest) error {
+	expectVersion, expectValue := request.ExpectVersion != 0, len(request.ExpectValue) > 0
+	switch {
+	case expectVersion && expectValue:
+		return ErrPreconditionFailed
+	case expectVersion:
+		return ErrVersionMismatch
+	case expectValue:
+		return ErrValueMismatch
+	default:
+		return ErrNotApplied
+	}
+}
+
+// migrate upgrades the given value with the migrator, writing back upgraded values with the given set function
+// If the write-back succeeds, the upgraded value is returned with the new version. If the write-back fails, the
+// upgraded value is returned with the version that was read.
+func migrate(value []byte, version uint64, migrator func([]byte, uint64) ([]byte, bool), set func([]byte, uint64) (uint64, error)) ([]byte, uint64) {
+	upgraded, ok := migrator(value, version)
+	if !ok {
+		return value, version
+	}
+	if version == 0 {
+		return upgraded, version
+	}
+	if newVersion, err := set(upgraded, version); err == nil {
+		return upgraded, newVersion
+	}
+	return upgraded, version
+}
+
+// addWatcher registers the given channel as a watcher, returning false if it's already watching
+func (v *value) addWatcher(ch chan<- *Event) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.watchers[ch] {
+		return false
+	}
+	v.watchers[ch] = true
+	return true
+}
+
+// removeWatcher unregisters the given channel
+func (v *value) removeWatcher(ch chan<- *Event) {
+	v.mu.Lock()
+	delete(v.watchers, ch)
+	v.mu.Unlock()
+}
+
+func (v *value) Watch(ctx context.Context, ch chan<- *Event, opts ...WatchOption) error {
+	if !v.addWatcher(ch) {
+		return ErrAlreadyWatching
+	}
+
+	options := &watchOptions{}
+	for _, opt := range opts {
+		opt.applyWatch(options)
+	}
+	if options.stats != nil {
+		ctx = util.NewWatchStatsContext(ctx, options.stats)
+	}
+
+	stream, err := v.session.DoCommandStream(ctx, watchOp, func(ctx context.Context, conn *grpc.ClientConn, header *headers.RequestHeader) (interface{}, error) {
 		client := api.NewValueServiceClient(conn)
 		request := &api.EventRequest{
 			Header: header,
@@ -175,23 +334,79 @@ func (v *value) Watch(ctx context.Context, ch chan<- *Event) error {
 		return response.Header, response, nil
 	})
 	if err != nil {
+		v.removeWatcher(ch)
 		return err
 	}
 
+	filter := &noopFilter{equal: options.equal}
+
+	send := func(event *Event) {
+		deliver(ch, event, options.stats)
+	}
+	var buffer *util.EventBuffer
+	if options.bufferSize > 0 {
+		buffer = util.NewEventBuffer(options.bufferSize, options.overflow, func(event interface{}) {
+			deliver(ch, event.(*Event), options.stats)
+		}, options.stats.DroppedFunc(options.dropped))
+		send = func(event *Event) {
+			buffer.Push(event)
+		}
+	}
+
 	go func() {
 		defer close(ch)
+		defer v.removeWatcher(ch)
 		for event := range stream {
 			response := event.(*api.EventResponse)
-			ch <- &Event{
-				Type:    EventUpdated,
-				Value:   response.NewValue,
-				Version: response.NewVersion,
+			valueEvent := newEvent(response)
+			if !options.accepts(valueEvent.Type) || filter.isNoop(response.NewValue) {
+				continue
 			}
+			send(valueEvent)
+		}
+		if buffer != nil {
+			buffer.Close()
 		}
 	}()
 	return nil
 }
 
+// deliver sends the event to the watch's channel, recording its delivery in the watch's stats
+func deliver(ch chan<- *Event, event *Event, stats *util.WatchStats) {
+	ch <- event
+	stats.RecordVersion(event.Version)
+	stats.RecordDelivered()
+}
+
+// newEvent returns the Event for the given event response
+func newEvent(response *api.EventResponse) *Event {
+	return &Event{
+		Type:    EventUpdated,
+		Value:   response.NewValue,
+		Version: response.NewVersion,
+	}
+}
+
+// noopFilter detects events whose value is equal to the last delivered value
+type noopFilter struct {
+	equal     func(a, b []byte) bool
+	last      []byte
+	delivered bool
+}
+
+// isNoop returns whether the given value is equal to the last delivered value, otherwise recording it as delivered
+func (f *noopFilter) isNoop(value []byte) bool {
+	if f.equal == nil {
+		return false
+	}
+	if f.delivered && f.equal(f.last, value) {
+		return true
+	}
+	f.last = value
+	f.delivered = true
+	return false
+}
+
 func (v *value) Close() error {
 	return v.session.Close()
 }
@@ -16,10 +16,16 @@ package value
 
 import (
 	"context"
+	"errors"
+	api "github.com/atomix/api/proto/atomix/value"
 	"github.com/atomix/go-client/pkg/client/primitive"
 	"github.com/atomix/go-client/pkg/client/session"
 	"github.com/atomix/go-client/pkg/client/test"
 	"github.com/stretchr/testify/assert"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -31,6 +37,10 @@ func TestValue(t *testing.T) {
 	value, err := New(context.TODO(), name, conns, session.WithTimeout(5*time.Second))
 	assert.NoError(t, err)
 	assert.NotNil(t, value)
+	assert.Equal(t, Type, value.Metadata().Type)
+	assert.Equal(t, "test", value.Metadata().Name.Group)
+	assert.Len(t, value.Sessions(), 1)
+	assert.True(t, value.Sessions()[0].Healthy())
 
 	val, version, err := value.Get(context.TODO())
 	assert.NoError(t, err)
@@ -42,6 +52,9 @@ func TestValue(t *testing.T) {
 	err = value.Watch(context.TODO(), ch)
 	assert.NoError(t, err)
 
+	err = value.Watch(context.TODO(), ch)
+	assert.Equal(t, ErrAlreadyWatching, err)
+
 	_, err = value.Set(context.TODO(), []byte("foo"), IfVersion(1))
 	assert.EqualError(t, err, "version mismatch")
 
@@ -60,6 +73,12 @@ func TestValue(t *testing.T) {
 	_, err = value.Set(context.TODO(), []byte("foo"), IfVersion(2))
 	assert.EqualError(t, err, "version mismatch")
 
+	_, err = value.Set(context.TODO(), []byte("foo"), IfVersion(2), IfValue([]byte("foo")))
+	assert.Equal(t, ErrPreconditionFailed, err)
+
+	_, err = value.Set(context.TODO(), []byte("foo"), IfVersion(1), IfValue([]byte("bar")))
+	assert.Equal(t, ErrPreconditionFailed, err)
+
 	version, err = value.Set(context.TODO(), []byte("bar"), IfVersion(1))
 	assert.NoError(t, err)
 	assert.Equal(t, uint64(2), version)
@@ -124,3 +143,336 @@ func TestValue(t *testing.T) {
 
 	test.StopTestPartitions(partitions)
 }
+
+func TestValueGetAndSet(t *testing.T) {
+	conns, partitions := test.StartTestPartitions(1)
+
+	name := primitive.NewName("default", "test", "default", "test")
+	value, err := New(context.TODO(), name, conns, session.WithTimeout(5*time.Second))
+	assert.NoError(t, err)
+
+	prev, prevVersion, version, err := value.GetAndSet(context.TODO(), []byte("foo"))
+	assert.NoError(t, err)
+	assert.Nil(t, prev)
+	assert.Equal(t, uint64(0), prevVersion)
+	assert.NotEqual(t, uint64(0), version)
+
+	prev, prevVersion, newVersion, err := value.GetAndSet(context.TODO(), []byte("bar"))
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", string(prev))
+	assert.Equal(t, version, prevVersion)
+	assert.True(t, newVersion > version)
+
+	val, version, err := value.Get(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", string(val))
+	assert.Equal(t, newVersion, version)
+
+	err = value.Delete()
+	assert.NoError(t, err)
+
+	test.StopTestPartitions(partitions)
+}
+
+func TestValueDuplicateWatch(t *testing.T) {
+	v := &value{
+		watchers: make(map[chan<- *Event]bool),
+	}
+
+	ch := make(chan *Event)
+	assert.True(t, v.addWatcher(ch))
+	err := v.Watch(context.TODO(), ch)
+	assert.Equal(t, ErrAlreadyWatching, err)
+
+	v.removeWatcher(ch)
+	assert.True(t, v.addWatcher(ch))
+	assert.True(t, v.addWatcher(make(chan *Event)))
+}
+
+func TestValueSuppressNoop(t *testing.T) {
+	options := &watchOptions{}
+	WithSuppressNoop().applyWatch(options)
+	filter := &noopFilter{equal: options.equal}
+	assert.False(t, filter.isNoop([]byte("foo")))
+	assert.True(t, filter.isNoop([]byte("foo")))
+	assert.False(t, filter.isNoop([]byte("bar")))
+	assert.False(t, filter.isNoop([]byte("foo")))
+
+	options = &watchOptions{}
+	WithSuppressNoopFunc(func(a, b []byte) bool {
+		return strings.EqualFold(string(a), string(b))
+	}).applyWatch(options)
+	filter = &noopFilter{equal: options.equal}
+	assert.False(t, filter.isNoop([]byte("foo")))
+	assert.True(t, filter.isNoop([]byte("FOO")))
+	assert.False(t, filter.isNoop([]byte("bar")))
+
+	filter = &noopFilter{}
+	assert.False(t, filter.isNoop([]byte("foo")))
+	assert.False(t, filter.isNoop([]byte("foo")))
+}
+
+func TestValueMigrate(t *testing.T) {
+	migrator := func(old []byte, version uint64) ([]byte, bool) {
+		if strings.HasPrefix(string(old), "v2:") {
+			return old, false
+		}
+		return []byte("v2:" + string(old)), true
+	}
+
+	writes := 0
+	set := func(value []byte, version uint64) (uint64, error) {
+		writes++
+		assert.Equal(t, uint64(1), version)
+		return version + 1, nil
+	}
+
+	value, version := migrate([]byte("foo"), 1, migrator, set)
+	assert.Equal(t, "v2:foo", string(value))
+	assert.Equal(t, uint64(2), version)
+	assert.Equal(t, 1, writes)
+
+	value, version = migrate([]byte("v2:foo"), 1, migrator, set)
+	assert.Equal(t, "v2:foo", string(value))
+	assert.Equal(t, uint64(1), version)
+	assert.Equal(t, 1, writes)
+
+	value, version = migrate([]byte("foo"), 1, migrator, func(value []byte, version uint64) (uint64, error) {
+		return 0, errors.New("version mismatch")
+	})
+	assert.Equal(t, "v2:foo", string(value))
+	assert.Equal(t, uint64(1), version)
+
+	value, version = migrate(nil, 0, migrator, set)
+	assert.Equal(t, "v2:", string(value))
+	assert.Equal(t, uint64(0), version)
+	assert.Equal(t, 1, writes)
+}
+
+// staticValue is a Value whose Get returns a fixed result
+type staticValue struct {
+	Value
+	result Result
+}
+
+func (v *staticValue) Get(ctx context.Context, opts ...GetOption) ([]byte, uint64, error) {
+	return v.result.Value, v.result.Version, v.result.Err
+}
+
+func TestGetAll(t *testing.T) {
+	values := make([]Value, 0, 3*getAllConcurrency)
+	for i := 0; i < getAllConcurrency; i++ {
+		values = append(values,
+			&staticValue{result: Result{Value: []byte("foo"), Version: uint64(i + 1)}},
+			&staticValue{},
+			&staticValue{result: Result{Err: errors.New("unavailable")}})
+	}
+
+	results, err := GetAll(context.TODO(), values)
+	assert.NoError(t, err)
+	assert.Len(t, results, len(values))
+	for i := 0; i < getAllConcurrency; i++ {
+		existing := results[i*3]
+		assert.NoError(t, existing.Err)
+		assert.Equal(t, "foo", string(existing.Value))
+		assert.Equal(t, uint64(i+1), existing.Version)
+
+		missing := results[i*3+1]
+		assert.NoError(t, missing.Err)
+		assert.Nil(t, missing.Value)
+		assert.Equal(t, uint64(0), missing.Version)
+
+		failed := results[i*3+2]
+		assert.EqualError(t, failed.Err, "unavailable")
+	}
+
+	results, err = GetAll(context.TODO(), nil)
+	assert.NoError(t, err)
+	assert.Len(t, results, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = GetAll(ctx, values)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestValueMismatchError(t *testing.T) {
+	request := &api.SetRequest{ExpectVersion: 1}
+	assert.Equal(t, ErrVersionMismatch, mismatchError(request))
+
+	request = &api.SetRequest{ExpectValue: []byte("foo")}
+	assert.Equal(t, ErrValueMismatch, mismatchError(request))
+
+	// With both conditions set, the failed condition is not known
+	request = &api.SetRequest{ExpectVersion: 1, ExpectValue: []byte("foo")}
+	assert.Equal(t, ErrPreconditionFailed, mismatchError(request))
+
+	// Without conditions, there's nothing to mismatch
+	request = &api.SetRequest{Value: []byte("foo")}
+	assert.Equal(t, ErrNotApplied, mismatchError(request))
+}
+
+// eventValue is a Value whose Watch delivers a fixed sequence of events
+type eventValue struct {
+	Value
+	events []*Event
+	sent   int32
+	closed chan struct{}
+}
+
+func (v *eventValue) Watch(ctx context.Context, ch chan<- *Event, opts ...WatchOption) error {
+	go func() {
+		defer close(ch)
+		defer close(v.closed)
+		for _, event := range v.events {
+			select {
+			case ch <- event:
+				atomic.AddInt32(&v.sent, 1)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func TestWatchAck(t *testing.T) {
+	events := []*Event{
+		{Type: EventUpdated, Value: []byte("a"), Version: 1},
+		{Type: EventUpdated, Value: []byte("b"), Version: 2},
+		{Type: EventUpdated, Value: []byte("c"), Version: 3},
+	}
+
+	// Events are delivered in order and the next event is not pulled until the current event is acknowledged
+	value := &eventValue{events: events, closed: make(chan struct{})}
+	var versions []uint64
+	err := WatchAck(context.TODO(), value, func(event *Event) error {
+		versions = append(versions, event.Version)
+		assert.True(t, atomic.LoadInt32(&value.sent) <= int32(len(versions)))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2, 3}, versions)
+
+	// A failed event is redelivered before the next event
+	value = &eventValue{events: events, closed: make(chan struct{})}
+	versions = nil
+	failed := false
+	err = WatchAck(context.TODO(), value, func(event *Event) error {
+		versions = append(versions, event.Version)
+		if event.Version == 2 && !failed {
+			failed = true
+			return errors.New("failed")
+		}
+		return nil
+	}, WithAckRetry(1, time.Millisecond))
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2, 2, 3}, versions)
+
+	// Once retries are exhausted the watch is closed and the handler's error is returned
+	value = &eventValue{events: events, closed: make(chan struct{})}
+	versions = nil
+	err = WatchAck(context.TODO(), value, func(event *Event) error {
+		versions = append(versions, event.Version)
+		if event.Version == 2 {
+			return errors.New("failed")
+		}
+		return nil
+	}, WithAckRetry(2, time.Millisecond))
+	assert.EqualError(t, err, "failed")
+	assert.Equal(t, []uint64{1, 2, 2, 2}, versions)
+	<-value.closed
+	assert.Equal(t, int32(2), atomic.LoadInt32(&value.sent))
+}
+
+// conflictValue is a Value whose conditional writes always conflict
+type conflictValue struct {
+	Value
+	sets int
+}
+
+func (v *conflictValue) Set(ctx context.Context, value []byte, opts ...SetOption) (uint64, error) {
+	v.sets++
+	return 0, ErrVersionMismatch
+}
+
+func TestValueUpdate(t *testing.T) {
+	name := primitive.NewName("default", "test", "default", "test")
+	value := newFake(name)
+	_, err := value.Set(context.TODO(), []byte("0"))
+	assert.NoError(t, err)
+
+	increment := func(old []byte, version uint64) ([]byte, error) {
+		i, err := strconv.Atoi(string(old))
+		if err != nil {
+			return nil, err
+		}
+		return []byte(strconv.Itoa(i + 1)), nil
+	}
+
+	// Each conflict is caused by another updater's successful write, so no updater can conflict more than
+	// the total number of updates
+	const updaters, updates = 10, 50
+	wg := &sync.WaitGroup{}
+	for i := 0; i < updaters; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < updates; j++ {
+				_, err := value.Update(context.TODO(), increment, WithUpdateRetries(updaters*updates))
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	current, version, err := value.Get(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(updaters*updates), string(current))
+	assert.Equal(t, uint64(updaters*updates+1), version)
+
+	// An aborted update does not write the value
+	_, err = value.Update(context.TODO(), func(old []byte, version uint64) ([]byte, error) {
+		return nil, ErrAbort
+	})
+	assert.Equal(t, ErrAbort, err)
+	_, abortedVersion, err := value.Get(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, version, abortedVersion)
+
+	// Errors from the update function are returned as is
+	_, err = value.Update(context.TODO(), func(old []byte, version uint64) ([]byte, error) {
+		return nil, errors.New("failed")
+	})
+	assert.EqualError(t, err, "failed")
+
+	// Once retries are exhausted the conflict is returned
+	conflict := &conflictValue{Value: value}
+	_, err = Update(context.TODO(), conflict, increment, WithUpdateRetries(3))
+	assert.Equal(t, ErrVersionMismatch, err)
+	assert.Equal(t, 4, conflict.sets)
+}
+
+func TestValueOperationNames(t *testing.T) {
+	conns, partitions := test.StartTestPartitions(1)
+
+	// Operations are reported to tracers and metrics by their primitive operation names
+	recorder := &test.OperationRecorder{}
+	name := primitive.NewName("default", "test", "default", "test")
+	value, err := New(context.TODO(), name, conns, append(recorder.Options(), session.WithTimeout(5*time.Second))...)
+	assert.NoError(t, err)
+
+	_, err = value.Set(context.TODO(), []byte("foo"))
+	assert.NoError(t, err)
+	_, _, err = value.Get(context.TODO())
+	assert.NoError(t, err)
+
+	for _, op := range []string{"value.Set", "value.Get"} {
+		assert.Contains(t, recorder.Spans(), op)
+		assert.Contains(t, recorder.Observed(), op)
+	}
+
+	err = value.Close()
+	assert.NoError(t, err)
+	test.StopTestPartitions(partitions)
+}
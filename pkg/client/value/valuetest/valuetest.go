@@ -0,0 +1,28 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package valuetest
+
+import (
+	"github.com/atomix/go-client/pkg/client/primitive"
+	"github.com/atomix/go-client/pkg/client/value"
+	"github.com/atomix/go-client/pkg/client/value/internal/fake"
+)
+
+// NewFake returns an in-memory Value for testing code that depends on Value
+// The fake implements the semantics of Value without a session or a server: each write increments the version,
+// IfVersion and IfValue conditions are honored, and changes are published to watchers. The fake has no sessions.
+func NewFake(name primitive.Name) value.Value {
+	return fake.NewValue(name).(value.Value)
+}
@@ -0,0 +1,43 @@
+// Copyright 2019-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package valuetest
+
+import (
+	"context"
+	"github.com/atomix/go-client/pkg/client/primitive"
+	"github.com/atomix/go-client/pkg/client/value"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFake(t *testing.T) {
+	v := NewFake(primitive.NewName("default", "test", "default", "test"))
+	assert.Equal(t, value.Type, v.Metadata().Type)
+
+	version, err := v.Set(context.TODO(), []byte("foo"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), version)
+
+	_, err = v.Set(context.TODO(), []byte("bar"), value.IfVersion(2))
+	assert.Equal(t, value.ErrVersionMismatch, err)
+
+	val, version, err := v.Get(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", string(val))
+	assert.Equal(t, uint64(1), version)
+
+	assert.NoError(t, v.Delete())
+	assert.Equal(t, value.ErrNotFound, v.Delete())
+}